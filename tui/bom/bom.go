@@ -0,0 +1,164 @@
+// Package bom builds bills of materials from the parts catalog and writes
+// them as CSV, JSON, Markdown or printable HTML.
+package bom
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"delica-tui/db"
+)
+
+// Formats lists the supported output formats, by file extension
+var Formats = []string{"csv", "json", "md", "html"}
+
+// BOM is a titled list of parts, split into one section per diagram.
+type BOM struct {
+	Title    string
+	Sections []Section
+}
+
+// Section is the parts drawn on one diagram.
+type Section struct {
+	Title   string
+	Diagram string // Absolute path of the diagram image, or "" if there is none
+	Parts   []db.PartWithDiagram
+}
+
+// ForSubgroup builds the BOM for one subgroup
+func ForSubgroup(database *db.DB, dataPath, subgroupID string) (*BOM, error) {
+	subgroup, err := database.GetSubgroup(subgroupID)
+	if err != nil {
+		return nil, err
+	}
+	if subgroup == nil {
+		return nil, fmt.Errorf("no subgroup %s", subgroupID)
+	}
+
+	title := subgroup.Name
+	if group, _ := database.GetGroup(subgroup.GroupID); group != nil {
+		title = group.Name + " > " + subgroup.Name
+	}
+
+	parts, err := database.GetPartsForSubgroup(subgroupID)
+	if err != nil {
+		return nil, err
+	}
+	return FromParts(database, dataPath, title, parts), nil
+}
+
+// ForGroup builds the BOM for every subgroup in a group
+func ForGroup(database *db.DB, dataPath, groupID string) (*BOM, error) {
+	group, err := database.GetGroup(groupID)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, fmt.Errorf("no group %s", groupID)
+	}
+
+	subgroups, err := database.GetSubgroups(groupID)
+	if err != nil {
+		return nil, err
+	}
+	var parts []db.PartWithDiagram
+	for _, s := range subgroups {
+		subgroupParts, err := database.GetPartsForSubgroup(s.ID)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, subgroupParts...)
+	}
+	return FromParts(database, dataPath, group.Name, parts), nil
+}
+
+// ForTag builds the BOM for every part with a tag
+func ForTag(database *db.DB, dataPath, tagID string) (*BOM, error) {
+	tag, err := database.GetTag(tagID)
+	if err != nil {
+		return nil, err
+	}
+	if tag == nil {
+		return nil, fmt.Errorf("no tag %s", tagID)
+	}
+
+	parts, err := database.GetPartsForTag(tagID)
+	if err != nil {
+		return nil, err
+	}
+	return FromParts(database, dataPath, tag.Name, parts), nil
+}
+
+// FromParts builds a BOM from a list of parts, starting a new section each
+// time the diagram changes
+func FromParts(database *db.DB, dataPath, title string, parts []db.PartWithDiagram) *BOM {
+	b := &BOM{Title: title}
+	for _, p := range parts {
+		n := len(b.Sections)
+		if n > 0 && b.Sections[n-1].Parts[0].DiagramID == p.DiagramID {
+			b.Sections[n-1].Parts = append(b.Sections[n-1].Parts, p)
+			continue
+		}
+
+		section := Section{Title: p.DiagramID, Parts: []db.PartWithDiagram{p}}
+		if diagram, _ := database.GetDiagram(p.DiagramID); diagram != nil {
+			section.Title = diagram.Name
+		}
+		if p.ImagePath != nil {
+			section.Diagram = filepath.Join(dataPath, *p.ImagePath)
+		}
+		b.Sections = append(b.Sections, section)
+	}
+	return b
+}
+
+// Count returns the number of parts in the BOM
+func (b *BOM) Count() int {
+	n := 0
+	for _, s := range b.Sections {
+		n += len(s.Parts)
+	}
+	return n
+}
+
+// Save writes the BOM to a timestamped file in dir, named after name, and
+// returns its path
+func Save(b *BOM, dir, name, format string) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create exports dir: %w", err)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.%s", Slug(name), time.Now().Format("20060102-150405"), format))
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("create export: %w", err)
+	}
+
+	if err := Write(f, b, format, dir); err != nil {
+		f.Close()
+		return "", fmt.Errorf("write export: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("write export: %w", err)
+	}
+	return path, nil
+}
+
+// Slug turns a title into a file name
+func Slug(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
@@ -0,0 +1,219 @@
+package bom
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"delica-tui/db"
+)
+
+// columns are the fields written for each part
+var columns = []string{"Ref", "PNC", "Part Number", "Description", "Quantity", "Spec", "Color", "Date Range", "Replacement"}
+
+// row returns a part's fields in column order
+func row(p db.PartWithDiagram) []string {
+	quantity := ""
+	if p.Quantity != nil {
+		quantity = fmt.Sprintf("%d", *p.Quantity)
+	}
+	return []string{
+		deref(p.RefNumber),
+		deref(p.PNC),
+		p.PartNumber,
+		deref(p.Description),
+		quantity,
+		deref(p.Spec),
+		deref(p.Color),
+		deref(p.ModelDateRange),
+		deref(p.ReplacementPartNumber),
+	}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// Write writes the BOM in a format. Markdown links to diagram images
+// relative to dir, where the file will be saved ("" for absolute paths);
+// HTML embeds the images so the file stands alone.
+func Write(w io.Writer, b *BOM, format, dir string) error {
+	switch format {
+	case "csv":
+		return writeCSV(w, b)
+	case "json":
+		return writeJSON(w, b)
+	case "md":
+		return writeMarkdown(w, b, dir)
+	case "html":
+		return writeHTML(w, b)
+	}
+	return fmt.Errorf("unknown format %q (want %s)", format, strings.Join(Formats, ", "))
+}
+
+func writeCSV(w io.Writer, b *BOM) error {
+	cw := csv.NewWriter(w)
+	cw.Write(append([]string{"Diagram"}, columns...))
+	for _, s := range b.Sections {
+		for _, p := range s.Parts {
+			cw.Write(append([]string{s.Title}, row(p)...))
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+type jsonBOM struct {
+	Title    string        `json:"title"`
+	Sections []jsonSection `json:"sections"`
+}
+
+type jsonSection struct {
+	Title   string     `json:"title"`
+	Diagram string     `json:"diagram,omitempty"`
+	Parts   []jsonPart `json:"parts"`
+}
+
+type jsonPart struct {
+	Ref         *string `json:"ref"`
+	PNC         *string `json:"pnc"`
+	PartNumber  string  `json:"part_number"`
+	Description *string `json:"description"`
+	Quantity    *int    `json:"quantity"`
+	Spec        *string `json:"spec"`
+	Color       *string `json:"color"`
+	DateRange   *string `json:"date_range"`
+	Replacement *string `json:"replacement"`
+}
+
+func writeJSON(w io.Writer, b *BOM) error {
+	out := jsonBOM{Title: b.Title, Sections: []jsonSection{}}
+	for _, s := range b.Sections {
+		section := jsonSection{Title: s.Title, Diagram: s.Diagram}
+		for _, p := range s.Parts {
+			section.Parts = append(section.Parts, jsonPart{
+				Ref:         p.RefNumber,
+				PNC:         p.PNC,
+				PartNumber:  p.PartNumber,
+				Description: p.Description,
+				Quantity:    p.Quantity,
+				Spec:        p.Spec,
+				Color:       p.Color,
+				DateRange:   p.ModelDateRange,
+				Replacement: p.ReplacementPartNumber,
+			})
+		}
+		out.Sections = append(out.Sections, section)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func writeMarkdown(w io.Writer, b *BOM, dir string) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n%d parts\n", b.Title, b.Count())
+
+	cell := strings.NewReplacer("|", `\|`, "\n", " ")
+	for _, s := range b.Sections {
+		fmt.Fprintf(&sb, "\n## %s\n\n", s.Title)
+		if s.Diagram != "" {
+			path := s.Diagram
+			if dir != "" {
+				if rel, err := filepath.Rel(dir, s.Diagram); err == nil {
+					path = rel
+				}
+			}
+			fmt.Fprintf(&sb, "![%s](<%s>)\n\n", s.Title, filepath.ToSlash(path))
+		}
+
+		sb.WriteString("| " + strings.Join(columns, " | ") + " |\n")
+		sb.WriteString(strings.Repeat("|---", len(columns)) + "|\n")
+		for _, p := range s.Parts {
+			fields := row(p)
+			for i := range fields {
+				fields[i] = cell.Replace(fields[i])
+			}
+			sb.WriteString("| " + strings.Join(fields, " | ") + " |\n")
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// htmlStyle lays the sheet out for printing, one diagram per page
+const htmlStyle = `body { font-family: sans-serif; font-size: 11pt; margin: 2em; }
+h2 { margin-top: 2em; }
+img { max-width: 100%; max-height: 60vh; display: block; margin: 1em 0; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #999; padding: 4px 6px; text-align: left; vertical-align: top; }
+th { background: #eee; }
+td.check { width: 1.5em; }
+@media print {
+  body { margin: 0; }
+  section { break-before: page; }
+  section:first-of-type { break-before: auto; }
+}`
+
+func writeHTML(w io.Writer, b *BOM) error {
+	var sb strings.Builder
+	esc := html.EscapeString
+
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&sb, "<title>%s</title>\n<style>\n%s\n</style>\n</head>\n<body>\n", esc(b.Title), htmlStyle)
+	fmt.Fprintf(&sb, "<h1>%s</h1>\n<p>%d parts</p>\n", esc(b.Title), b.Count())
+
+	for _, s := range b.Sections {
+		fmt.Fprintf(&sb, "<section>\n<h2>%s</h2>\n", esc(s.Title))
+		if src := dataURI(s.Diagram); src != "" {
+			fmt.Fprintf(&sb, "<img src=\"%s\" alt=\"%s\">\n", src, esc(s.Title))
+		}
+
+		// A blank first column to tick parts off at the workbench
+		sb.WriteString("<table>\n<tr><th></th>")
+		for _, c := range columns {
+			fmt.Fprintf(&sb, "<th>%s</th>", esc(c))
+		}
+		sb.WriteString("</tr>\n")
+		for _, p := range s.Parts {
+			sb.WriteString("<tr><td class=\"check\"></td>")
+			for _, f := range row(p) {
+				fmt.Fprintf(&sb, "<td>%s</td>", esc(f))
+			}
+			sb.WriteString("</tr>\n")
+		}
+		sb.WriteString("</table>\n</section>\n")
+	}
+
+	sb.WriteString("</body>\n</html>\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// dataURI reads an image into a data: URI, or returns "" if it can't be read
+func dataURI(path string) string {
+	if path == "" {
+		return ""
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	mediaType := mime.TypeByExtension(strings.ToLower(filepath.Ext(path)))
+	if mediaType == "" {
+		mediaType = "image/png"
+	}
+	return "data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
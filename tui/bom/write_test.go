@@ -0,0 +1,125 @@
+package bom
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"delica-tui/db"
+)
+
+func testBOM(t *testing.T) (*BOM, string) {
+	t.Helper()
+	dir := t.TempDir()
+	diagram := filepath.Join(dir, "images", "d1.png")
+	if err := os.MkdirAll(filepath.Dir(diagram), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(diagram, []byte("png"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	str := func(s string) *string { return &s }
+	qty := 2
+	return &BOM{
+		Title: "Engine > Cylinder head",
+		Sections: []Section{
+			{
+				Title:   "Cylinder head",
+				Diagram: diagram,
+				Parts: []db.PartWithDiagram{
+					{Part: db.Part{RefNumber: str("1"), PNC: str("11101"), PartNumber: "MD123456",
+						Description: str("GASKET,CYLINDER HEAD"), Quantity: &qty, Spec: str("4M40")}},
+					{Part: db.Part{PartNumber: "MD654321", Description: str("BOLT | <M10>"),
+						ReplacementPartNumber: str("MD654322")}},
+				},
+			},
+			{
+				Title: "Water pump",
+				Parts: []db.PartWithDiagram{{Part: db.Part{PartNumber: "MD050123", Color: str("W09M")}}},
+			},
+		},
+	}, dir
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		format string
+		want   []string // Expected in the output, in order
+	}{
+		{"csv", []string{
+			"Diagram,Ref,PNC,Part Number,Description,Quantity,Spec,Color,Date Range,Replacement\n" +
+				"Cylinder head,1,11101,MD123456,\"GASKET,CYLINDER HEAD\",2,4M40,,,\n" +
+				"Cylinder head,,,MD654321,BOLT | <M10>,,,,,MD654322\n" +
+				"Water pump,,,MD050123,,,,W09M,,\n",
+		}},
+		{"json", []string{
+			`"title": "Engine \u003e Cylinder head"`, // encoding/json escapes HTML
+			`"title": "Cylinder head"`,
+			`"diagram": "`,
+			`"part_number": "MD123456"`,
+			`"quantity": 2`,
+			`"replacement": "MD654322"`,
+			`"title": "Water pump",`,
+			`"color": "W09M"`,
+		}},
+		{"md", []string{
+			"# Engine > Cylinder head\n\n3 parts\n",
+			"\n## Cylinder head\n\n![Cylinder head](<images/d1.png>)\n\n",
+			"| Ref | PNC | Part Number | Description | Quantity | Spec | Color | Date Range | Replacement |\n",
+			"| 1 | 11101 | MD123456 | GASKET,CYLINDER HEAD | 2 | 4M40 |  |  |  |\n",
+			`| BOLT \| <M10> |`,
+			"\n## Water pump\n\n| Ref",
+		}},
+		{"html", []string{
+			"<title>Engine &gt; Cylinder head</title>",
+			"<h1>Engine &gt; Cylinder head</h1>\n<p>3 parts</p>",
+			`<img src="data:image/png;base64,cG5n" alt="Cylinder head">`,
+			`<tr><td class="check"></td><td>1</td><td>11101</td><td>MD123456</td>`,
+			"<td>BOLT | &lt;M10&gt;</td>",
+			"<h2>Water pump</h2>\n<table>",
+		}},
+	}
+	for _, tt := range tests {
+		b, dir := testBOM(t)
+		var out strings.Builder
+		if err := Write(&out, b, tt.format, dir); err != nil {
+			t.Errorf("Write(%s): %v", tt.format, err)
+			continue
+		}
+		rest := out.String()
+		for _, want := range tt.want {
+			i := strings.Index(rest, want)
+			if i < 0 {
+				t.Errorf("Write(%s) is missing %q after the earlier parts, got:\n%s", tt.format, want, out.String())
+				break
+			}
+			rest = rest[i+len(want):]
+		}
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	b, dir := testBOM(t)
+	var out strings.Builder
+	if err := Write(&out, b, "pdf", dir); err == nil {
+		t.Errorf("Write(pdf) didn't fail")
+	}
+}
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		title, want string
+	}{
+		{"Engine > Cylinder head", "engine-cylinder-head"},
+		{"  A/C  ", "a-c"},
+		{"Order list", "order-list"},
+		{"---", ""},
+	}
+	for _, tt := range tests {
+		if got := Slug(tt.title); got != tt.want {
+			t.Errorf("Slug(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
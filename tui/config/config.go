@@ -0,0 +1,135 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Config holds user preferences that persist between sessions.
+type Config struct {
+	Layouts map[string]Layout `json:"layouts,omitempty"`
+	Links   []LinkProvider    `json:"links,omitempty"`
+
+	// How parts that don't fit the vehicle are shown: "dim" (the default),
+	// "hide" or "all"
+	Applicability string `json:"applicability,omitempty"`
+
+	// Names for EPC color codes, e.g. {"W09M": "Sophia White"}
+	Colors map[string]string `json:"colors,omitempty"`
+
+	// Color theme: "auto" (the default, light or dark to suit the terminal),
+	// a built-in theme or one of Themes
+	Theme  string           `json:"theme,omitempty"`
+	Themes map[string]Theme `json:"themes,omitempty"`
+
+	path     string
+	readOnly bool // The file couldn't be loaded, so saving would overwrite it
+}
+
+// Layout is the saved split pane arrangement for one screen type.
+type Layout struct {
+	Ratio       int    `json:"ratio,omitempty"`
+	Orientation string `json:"orientation,omitempty"`
+	Hidden      string `json:"hidden,omitempty"`
+}
+
+// Theme is a custom color theme. Colors are ANSI numbers ("6") or hex
+// ("#5fafd7"); any left out come from the Base theme ("dark" by default).
+type Theme struct {
+	Base          string `json:"base,omitempty"`
+	Header        string `json:"header,omitempty"`
+	PartNumber    string `json:"part_number,omitempty"`
+	Selected      string `json:"selected,omitempty"`
+	SelectedLabel string `json:"selected_label,omitempty"`
+	Text          string `json:"text,omitempty"`
+	Dim           string `json:"dim,omitempty"`
+	Count         string `json:"count,omitempty"`
+	Error         string `json:"error,omitempty"`
+	Link          string `json:"link,omitempty"`
+	Match         string `json:"match,omitempty"`
+}
+
+// LinkProvider is an external site listed under a part's links. URL is a
+// template with {placeholders} for the part's fields.
+type LinkProvider struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
+// DefaultLinks are the providers used when the config lists none.
+var DefaultLinks = []LinkProvider{
+	{Label: "EPC", URL: "https://mitsubishi.epc-data.com/delica_space_gear/{frame_name}/{trim_code}/{subgroup}/{detail_page}/?frame_no={frame_no}"},
+	{Label: "Amayama", URL: "https://www.amayama.com/en/part/mitsubishi/{replacement}"},
+	{Label: "Amazon", URL: "https://www.amazon.com/s?k={replacement}"},
+}
+
+// errReadOnly is returned by Save when the config file couldn't be loaded
+var errReadOnly = errors.New("config file could not be loaded")
+
+// Load reads the config file at path. A missing file yields an empty config.
+// If the file can't be read or parsed, the config is read-only so that saving
+// doesn't replace the file with defaults.
+func Load(path string) (*Config, error) {
+	cfg := &Config{path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return &Config{path: path, readOnly: true}, fmt.Errorf("read config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return &Config{path: path, readOnly: true}, fmt.Errorf("parse config: %w", err)
+	}
+	return cfg, nil
+}
+
+// Save writes the config back to the file it was loaded from.
+func (c *Config) Save() error {
+	if c.path == "" {
+		return nil
+	}
+	if c.readOnly {
+		return errReadOnly
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode config: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("create config dir: %w", err)
+	}
+	if err := os.WriteFile(c.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+	return nil
+}
+
+// Layout returns the saved layout for a screen type.
+func (c *Config) Layout(screen string) (Layout, bool) {
+	l, ok := c.Layouts[screen]
+	return l, ok
+}
+
+// SetLayout records the layout for a screen type.
+func (c *Config) SetLayout(screen string, l Layout) {
+	if c.Layouts == nil {
+		c.Layouts = make(map[string]Layout)
+	}
+	c.Layouts[screen] = l
+}
+
+// LinkProviders returns the configured link providers, or the defaults.
+func (c *Config) LinkProviders() []LinkProvider {
+	if len(c.Links) == 0 {
+		return DefaultLinks
+	}
+	return c.Links
+}
@@ -0,0 +1,172 @@
+package db
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DateRange is a production window from the EPC, in months written as
+// YYYYMM. A zero end leaves the window open on that side.
+type DateRange struct {
+	From int
+	To   int
+}
+
+// ParseDateRange reads an EPC model date range such as "199707-200212",
+// "199707-" or "-199912". Months may also be written with separators
+// ("1997.07") or two-digit years ("9707"). An empty string is an open range.
+func ParseDateRange(s string) (DateRange, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return DateRange{}, nil
+	}
+
+	from, to, found := strings.Cut(strings.ReplaceAll(s, "~", "-"), "-")
+	if !found {
+		// A lone date is where production started
+		to = ""
+	}
+
+	var r DateRange
+	var err error
+	if r.From, err = parseMonth(from); err != nil {
+		return DateRange{}, fmt.Errorf("date range %q: %w", s, err)
+	}
+	if r.To, err = parseMonth(to); err != nil {
+		return DateRange{}, fmt.Errorf("date range %q: %w", s, err)
+	}
+	return r, nil
+}
+
+// Contains reports whether a YYYYMM month falls inside the range
+func (r DateRange) Contains(month int) bool {
+	return (r.From == 0 || month >= r.From) && (r.To == 0 || month <= r.To)
+}
+
+// ParseBuildDate reads the vehicle's manufacture date, as written in .env
+// ("1999.07.3"), into a YYYYMM month. Anything after the month is ignored.
+func ParseBuildDate(s string) (int, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == '.' || r == '-' || r == '/' })
+	switch {
+	case len(fields) >= 2:
+		return parseMonth(fields[0] + fmt.Sprintf("%02s", fields[1]))
+	case len(fields) == 1:
+		return parseMonth(fields[0])
+	}
+	return 0, fmt.Errorf("build date %q: no month", s)
+}
+
+// parseMonth reads YYYYMM or YYMM, ignoring separators; "" is 0
+func parseMonth(s string) (int, error) {
+	s = strings.Map(func(r rune) rune {
+		if r == '.' || r == '/' || r == ' ' {
+			return -1
+		}
+		return r
+	}, s)
+	if s == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("bad month %q", s)
+	}
+	switch len(s) {
+	case 4:
+		// Two-digit years: the EPC covers 1950 to 2049
+		year, month := n/100, n%100
+		if year < 50 {
+			year += 2000
+		} else {
+			year += 1900
+		}
+		n = year*100 + month
+	case 6:
+	default:
+		return 0, fmt.Errorf("bad month %q", s)
+	}
+	if month := n % 100; month < 1 || month > 12 {
+		return 0, fmt.Errorf("bad month %q", s)
+	}
+	return n, nil
+}
+
+// formatMonth writes a YYYYMM month the way .env does, as "1999.07"
+func formatMonth(month int) string {
+	return fmt.Sprintf("%d.%02d", month/100, month%100)
+}
+
+// Vehicle is the van parts are checked against. Unknown fields don't
+// exclude anything.
+type Vehicle struct {
+	Built      int         // Production month as YYYYMM, or 0 if unknown
+	Attributes []Attribute // Trim configuration, such as the engine and roof
+	Exterior   string      // Paint code, e.g. "W09M"
+	Interior   string      // Trim code, e.g. "57A"
+}
+
+// NewVehicle builds a Vehicle from the .env settings: MANUFACTURE_DATE and
+// text describing the trim configuration, such as VEHICLE_NAME
+// ("... (HIGH-ROOF), 4CA/T ...") and VEHICLE_SPEC ("4M40")
+func NewVehicle(manufactureDate string, trim ...string) Vehicle {
+	var v Vehicle
+	v.Built, _ = ParseBuildDate(manufactureDate)
+	v.Attributes = ParseAttributes(trim...)
+	return v
+}
+
+// Known reports whether there is anything to check parts against
+func (v Vehicle) Known() bool {
+	return v.Built != 0 || len(v.Attributes) > 0
+}
+
+// Fits reports whether a part fits the vehicle, and if not, why. Parts
+// with a date range that can't be read are assumed to fit.
+func (v Vehicle) Fits(p Part) (bool, string) {
+	if v.Built != 0 && p.ModelDateRange != nil {
+		r, err := ParseDateRange(*p.ModelDateRange)
+		if err == nil && !r.Contains(v.Built) {
+			if r.From != 0 && v.Built < r.From {
+				return false, fmt.Sprintf("made from %s, van built %s", formatMonth(r.From), formatMonth(v.Built))
+			}
+			return false, fmt.Sprintf("made until %s, van built %s", formatMonth(r.To), formatMonth(v.Built))
+		}
+	}
+
+	// A part for certain engines, roofs and so on fits if the van has one
+	// of them. Kinds the van's configuration doesn't mention are skipped.
+	partAttrs := PartAttributes(p)
+	for _, kind := range attributeKinds(partAttrs) {
+		ours := valuesOf(v.Attributes, kind)
+		theirs := valuesOf(partAttrs, kind)
+		if len(ours) > 0 && !slices.ContainsFunc(theirs, func(s string) bool { return slices.Contains(ours, s) }) {
+			return false, fmt.Sprintf("for %s, van is %s", strings.Join(theirs, " "), strings.Join(ours, " "))
+		}
+	}
+	return true, ""
+}
+
+// attributeKinds lists the kinds of some attributes in order, without repeats
+func attributeKinds(attrs []Attribute) []string {
+	var kinds []string
+	for _, a := range attrs {
+		if !slices.Contains(kinds, a.Kind) {
+			kinds = append(kinds, a.Kind)
+		}
+	}
+	return kinds
+}
+
+// valuesOf returns the values of the attributes of one kind
+func valuesOf(attrs []Attribute, kind string) []string {
+	var values []string
+	for _, a := range attrs {
+		if a.Kind == kind {
+			values = append(values, a.Value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,103 @@
+package db
+
+import "testing"
+
+func TestParseDateRange(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    DateRange
+		wantErr bool
+	}{
+		{"", DateRange{}, false},
+		{"199707-200212", DateRange{199707, 200212}, false},
+		{"199707-", DateRange{From: 199707}, false},
+		{"-199912", DateRange{To: 199912}, false},
+		{"199707", DateRange{From: 199707}, false},
+		{"1997.07-2002.12", DateRange{199707, 200212}, false},
+		{"9707-0212", DateRange{199707, 200212}, false},
+		{" 199707~200212 ", DateRange{199707, 200212}, false},
+		{"199713-", DateRange{}, true},
+		{"1997-2002", DateRange{}, true},
+		{"SEE NOTE", DateRange{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDateRange(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDateRange(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDateRange(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseBuildDate(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"1999.07.3", 199907, false},
+		{"1999.7", 199907, false},
+		{"1999-07", 199907, false},
+		{"1999/07/15", 199907, false},
+		{"199907", 199907, false},
+		{"9907", 199907, false},
+		{"", 0, true},
+		{"1999.13", 0, true},
+		{"July 1999", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseBuildDate(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseBuildDate(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseBuildDate(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestVehicleFits(t *testing.T) {
+	van := NewVehicle("1999.07.3", "DELICA SPACE GEAR (HIGH-ROOF), 4CA/T", "4M40")
+
+	tests := []struct {
+		name       string
+		vehicle    Vehicle
+		dates      string
+		spec       string
+		notes      string
+		want       bool
+		wantReason string
+	}{
+		{"no restrictions", van, "", "", "", true, ""},
+		{"inside dates", van, "199707-200212", "", "", true, ""},
+		{"made later", van, "200001-", "", "", false, "made from 2000.01, van built 1999.07"},
+		{"made earlier", van, "-199812", "", "", false, "made until 1998.12, van built 1999.07"},
+		{"unreadable dates", van, "SEE NOTE", "", "", true, ""},
+		{"same engine", van, "", "4M40", "", true, ""},
+		{"one of the engines", van, "", "4D56,4M40", "", true, ""},
+		{"other engine", van, "", "6G72", "", false, "for 6G72, van is 4M40"},
+		{"other roof", van, "", "", "STD ROOF", false, "for Standard roof, van is High roof"},
+		{"kind the van doesn't mention", van, "", "4M40 RHD", "", true, ""},
+		{"unknown van", Vehicle{}, "-199012", "6G72", "", true, ""},
+	}
+	for _, tt := range tests {
+		p := Part{}
+		if tt.dates != "" {
+			p.ModelDateRange = &tt.dates
+		}
+		if tt.spec != "" {
+			p.Spec = &tt.spec
+		}
+		if tt.notes != "" {
+			p.Notes = &tt.notes
+		}
+		got, reason := tt.vehicle.Fits(p)
+		if got != tt.want || reason != tt.wantReason {
+			t.Errorf("%s: Fits = %v, %q, want %v, %q", tt.name, got, reason, tt.want, tt.wantReason)
+		}
+	}
+}
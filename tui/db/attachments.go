@@ -0,0 +1,44 @@
+package db
+
+import (
+	"zombiezen.com/go/sqlite"
+	"zombiezen.com/go/sqlite/sqlitex"
+)
+
+// AddAttachment records a file stored for a part number
+func (d *DB) AddAttachment(partNumber, name, path string, size int64) error {
+	return sqlitex.ExecuteTransient(d.conn, "INSERT INTO attachments (part_number, name, path, size) VALUES (?, ?, ?, ?)", &sqlitex.ExecOptions{
+		Args: []any{partNumber, name, path, size},
+	})
+}
+
+func (d *DB) RemoveAttachment(id int) error {
+	return sqlitex.ExecuteTransient(d.conn, "DELETE FROM attachments WHERE id = ?", &sqlitex.ExecOptions{
+		Args: []any{id},
+	})
+}
+
+// GetAttachments returns the files stored for a part number, oldest first
+func (d *DB) GetAttachments(partNumber string) ([]Attachment, error) {
+	var attachments []Attachment
+	err := sqlitex.Execute(d.conn, `
+		SELECT id, part_number, name, path, size, created_at
+		FROM attachments
+		WHERE part_number = ?
+		ORDER BY created_at, id
+	`, &sqlitex.ExecOptions{
+		Args: []any{partNumber},
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			attachments = append(attachments, Attachment{
+				ID:         stmt.ColumnInt(0),
+				PartNumber: stmt.ColumnText(1),
+				Name:       stmt.ColumnText(2),
+				Path:       stmt.ColumnText(3),
+				Size:       stmt.ColumnInt64(4),
+				CreatedAt:  stmt.ColumnText(5),
+			})
+			return nil
+		},
+	})
+	return attachments, err
+}
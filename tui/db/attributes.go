@@ -0,0 +1,197 @@
+package db
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"zombiezen.com/go/sqlite"
+	"zombiezen.com/go/sqlite/sqlitex"
+)
+
+// Attribute is one structured fact pulled out of a part's spec and notes,
+// such as the engine it is for.
+type Attribute struct {
+	Kind  string // "engine", "transmission", "roof", "drive", "region" or "steering"
+	Value string // Display form, e.g. "4M40", "A/T", "High roof"
+}
+
+// Key encodes the attribute for a search filter, as "kind=value"
+func (a Attribute) Key() string {
+	return a.Kind + "=" + a.Value
+}
+
+// ParseAttributeKey reverses Key
+func ParseAttributeKey(key string) (Attribute, bool) {
+	kind, value, ok := strings.Cut(key, "=")
+	return Attribute{Kind: kind, Value: value}, ok && kind != "" && value != ""
+}
+
+// attributeVersion is bumped whenever the patterns change, so the stored
+// attributes are parsed again
+const attributeVersion = 2
+
+// engineCodes are the engines fitted to the Delica Space Gear and its
+// relatives in the catalog. Other digit-letter-digit tokens in spec and notes
+// text are dimensions or part codes, not engines.
+var engineCodes = []string{"4D56", "4G63", "4G64", "4M40", "4M41", "6G72", "6G74"}
+
+// attributePatterns match EPC spec and notes text, upper-cased. Each gives
+// the attribute's kind and its value, from the named group "v" if the
+// pattern has one.
+var attributePatterns = []struct {
+	kind  string
+	re    *regexp.Regexp
+	value string
+}{
+	{"engine", regexp.MustCompile(`\b(?P<v>` + strings.Join(engineCodes, "|") + `)\b`), ""},
+	{"transmission", regexp.MustCompile(`A/T|\bAT\b`), "A/T"},
+	{"transmission", regexp.MustCompile(`M/T|\bMT\b`), "M/T"},
+	{"roof", regexp.MustCompile(`\bHIGH[- ]?ROOF|\bHI[- ]ROOF|\bH/R\b`), "High roof"},
+	{"roof", regexp.MustCompile(`\b(STD|STANDARD|NORMAL)[- ]?ROOF`), "Standard roof"},
+	{"roof", regexp.MustCompile(`\bCRYSTAL[- ]?LITE?\b`), "Crystal Lite roof"},
+	{"drive", regexp.MustCompile(`\b(?P<v>[24]WD)\b`), ""},
+	{"region", regexp.MustCompile(`\b(JPN|JAPAN)\b`), "Japan"},
+	{"region", regexp.MustCompile(`\b(EUR|EUROPE)\b`), "Europe"},
+	{"region", regexp.MustCompile(`\b(GEN|GENERAL)\b`), "General export"},
+	{"region", regexp.MustCompile(`\b(AUS|AUSTRALIA)\b`), "Australia"},
+	{"steering", regexp.MustCompile(`\b(?P<v>[LR]HD)\b`), ""},
+}
+
+// ParseAttributes pulls structured attributes out of free-text spec and
+// notes fields, without repeats
+func ParseAttributes(texts ...string) []Attribute {
+	var attrs []Attribute
+	for _, text := range texts {
+		text = strings.ToUpper(text)
+		for _, p := range attributePatterns {
+			for _, m := range p.re.FindAllStringSubmatch(text, -1) {
+				value := p.value
+				if i := p.re.SubexpIndex("v"); i >= 0 {
+					value = m[i]
+				}
+				a := Attribute{Kind: p.kind, Value: value}
+				if !slices.Contains(attrs, a) {
+					attrs = append(attrs, a)
+				}
+			}
+		}
+	}
+	return attrs
+}
+
+// PartAttributes parses a part's spec and notes
+func PartAttributes(p Part) []Attribute {
+	var spec, notes string
+	if p.Spec != nil {
+		spec = *p.Spec
+	}
+	if p.Notes != nil {
+		notes = *p.Notes
+	}
+	return ParseAttributes(spec, notes)
+}
+
+// ensureAttributes creates the side table of parsed attributes and fills it
+// again whenever the parts or the parser have changed since it was built
+func ensureAttributes(conn *sqlite.Conn) (err error) {
+	err = sqlitex.ExecuteScript(conn, `
+		CREATE TABLE IF NOT EXISTS part_attributes (
+			part_id INTEGER NOT NULL,
+			kind TEXT NOT NULL,
+			value TEXT NOT NULL,
+			PRIMARY KEY (part_id, kind, value)
+		);
+		CREATE INDEX IF NOT EXISTS part_attributes_kind ON part_attributes(kind, value);
+		CREATE TABLE IF NOT EXISTS part_attributes_state (
+			version INTEGER NOT NULL,
+			signature TEXT NOT NULL
+		);
+	`, nil)
+	if err != nil {
+		return err
+	}
+
+	// The parts table only changes when the scraper runs, so its size and
+	// highest ID are enough to tell whether it has
+	var signature, stored string
+	version := 0
+	err = sqlitex.Execute(conn, "SELECT COUNT(*) || ':' || COALESCE(MAX(id), 0) FROM parts", &sqlitex.ExecOptions{
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			signature = stmt.ColumnText(0)
+			return nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+	err = sqlitex.Execute(conn, "SELECT version, signature FROM part_attributes_state", &sqlitex.ExecOptions{
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			version, stored = stmt.ColumnInt(0), stmt.ColumnText(1)
+			return nil
+		},
+	})
+	if err != nil || (version == attributeVersion && stored == signature) {
+		return err
+	}
+
+	defer sqlitex.Save(conn)(&err)
+	if err := sqlitex.ExecuteTransient(conn, "DELETE FROM part_attributes", nil); err != nil {
+		return err
+	}
+
+	type row struct {
+		id    int
+		attrs []Attribute
+	}
+	var rows []row
+	err = sqlitex.Execute(conn, "SELECT id, COALESCE(spec, ''), COALESCE(notes, '') FROM parts", &sqlitex.ExecOptions{
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			if attrs := ParseAttributes(stmt.ColumnText(1), stmt.ColumnText(2)); len(attrs) > 0 {
+				rows = append(rows, row{stmt.ColumnInt(0), attrs})
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for _, r := range rows {
+		for _, a := range r.attrs {
+			err := sqlitex.Execute(conn, "INSERT INTO part_attributes (part_id, kind, value) VALUES (?, ?, ?)", &sqlitex.ExecOptions{
+				Args: []any{r.id, a.Kind, a.Value},
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := sqlitex.ExecuteTransient(conn, "DELETE FROM part_attributes_state", nil); err != nil {
+		return err
+	}
+	return sqlitex.Execute(conn, "INSERT INTO part_attributes_state (version, signature) VALUES (?, ?)", &sqlitex.ExecOptions{
+		Args: []any{attributeVersion, signature},
+	})
+}
+
+// GetSubgroupAttributes returns the attributes of each part in a subgroup
+// that has any, by part ID
+func (d *DB) GetSubgroupAttributes(subgroupID string) (map[int][]Attribute, error) {
+	attrs := make(map[int][]Attribute)
+	err := sqlitex.Execute(d.conn, `
+		SELECT a.part_id, a.kind, a.value
+		FROM part_attributes a
+		JOIN parts p ON p.id = a.part_id
+		WHERE p.subgroup_id = ?
+		ORDER BY a.kind, a.value
+	`, &sqlitex.ExecOptions{
+		Args: []any{subgroupID},
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			id := stmt.ColumnInt(0)
+			attrs[id] = append(attrs[id], Attribute{Kind: stmt.ColumnText(1), Value: stmt.ColumnText(2)})
+			return nil
+		},
+	})
+	return attrs, err
+}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseAttributes(t *testing.T) {
+	tests := []struct {
+		name  string
+		texts []string
+		want  []Attribute
+	}{
+		{"nothing", []string{""}, nil},
+		{"engine", []string{"4m40"}, []Attribute{{"engine", "4M40"}}},
+		{"engines", []string{"4D56,4M40"}, []Attribute{{"engine", "4D56"}, {"engine", "4M40"}}},
+		{"not an engine", []string{"6X12 BOLT, 8A20"}, nil},
+		{"engine inside a part number", []string{"MD4M40X"}, nil},
+		{"transmission", []string{"4CA/T"}, []Attribute{{"transmission", "A/T"}}},
+		{"manual", []string{"5MT"}, nil},
+		{"manual word", []string{"MT"}, []Attribute{{"transmission", "M/T"}}},
+		{"high roof", []string{"DELICA (HIGH-ROOF)"}, []Attribute{{"roof", "High roof"}}},
+		{"crystal lite", []string{"CRYSTAL LITE"}, []Attribute{{"roof", "Crystal Lite roof"}}},
+		{"drive and steering", []string{"4WD,RHD"}, []Attribute{{"drive", "4WD"}, {"steering", "RHD"}}},
+		{"region", []string{"EUR"}, []Attribute{{"region", "Europe"}}},
+		{"spec and notes", []string{"4M40", "JPN"}, []Attribute{{"engine", "4M40"}, {"region", "Japan"}}},
+		{"no repeats", []string{"4M40", "4M40 A/T"}, []Attribute{{"engine", "4M40"}, {"transmission", "A/T"}}},
+	}
+	for _, tt := range tests {
+		got := ParseAttributes(tt.texts...)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: ParseAttributes(%q) = %v, want %v", tt.name, tt.texts, got, tt.want)
+		}
+	}
+}
+
+func TestParseAttributeKey(t *testing.T) {
+	tests := []struct {
+		key    string
+		want   Attribute
+		wantOK bool
+	}{
+		{"engine=4M40", Attribute{"engine", "4M40"}, true},
+		{"roof=High roof", Attribute{"roof", "High roof"}, true},
+		{"engine=", Attribute{"engine", ""}, false},
+		{"=4M40", Attribute{"", "4M40"}, false},
+		{"4M40", Attribute{"4M40", ""}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseAttributeKey(tt.key)
+		if ok != tt.wantOK || ok && got != tt.want {
+			t.Errorf("ParseAttributeKey(%q) = %v, %v, want %v, %v", tt.key, got, ok, tt.want, tt.wantOK)
+		}
+		if ok && got.Key() != tt.key {
+			t.Errorf("%v.Key() = %q, want %q", got, got.Key(), tt.key)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ColorCodes splits a part's color field into upper case codes
+func ColorCodes(s string) []string {
+	return strings.FieldsFunc(strings.ToUpper(s), func(r rune) bool {
+		return r == ',' || r == '/' || r == ';' || unicode.IsSpace(r)
+	})
+}
+
+// ColorName returns the name the config gives a color code, or "" if it
+// has none. The catalog itself doesn't name colors.
+func ColorName(code string, names map[string]string) string {
+	return names[strings.ToUpper(code)]
+}
+
+// ColorMatch reports which of a part's color codes is the vehicle's
+// exterior or interior color, and which of the two it is ("exterior" or
+// "interior"). It returns empty strings if none is, or the part has no color.
+func (v Vehicle) ColorMatch(p Part) (code, which string) {
+	if p.Color == nil {
+		return "", ""
+	}
+	for _, c := range ColorCodes(*p.Color) {
+		switch {
+		case sameColor(c, v.Exterior):
+			return c, "exterior"
+		case sameColor(c, v.Interior):
+			return c, "interior"
+		}
+	}
+	return "", ""
+}
+
+// sameColor matches a part's color code against one of the vehicle's. The
+// EPC sometimes drops the trailing variant letter ("57" for "57A"), so codes
+// also match when they differ only in that one side has no variant.
+func sameColor(code, vehicle string) bool {
+	vehicle = strings.ToUpper(strings.TrimSpace(vehicle))
+	if vehicle == "" || code == "" {
+		return false
+	}
+	if code == vehicle {
+		return true
+	}
+	codeBase, codeVariant := splitVariant(code)
+	vehicleBase, vehicleVariant := splitVariant(vehicle)
+	return codeBase == vehicleBase && (codeVariant == "" || vehicleVariant == "")
+}
+
+// splitVariant splits a color code ending in a digit and one letter into
+// the code and the variant letter ("W09M" is "W09" and "M"). Codes that
+// don't end that way have no variant.
+func splitVariant(code string) (string, string) {
+	n := len(code)
+	if n < 2 || !isLetter(code[n-1]) || !isDigit(code[n-2]) {
+		return code, ""
+	}
+	return code[:n-1], code[n-1:]
+}
+
+func isLetter(c byte) bool { return c >= 'A' && c <= 'Z' }
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
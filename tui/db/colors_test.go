@@ -0,0 +1,70 @@
+package db
+
+import "testing"
+
+func TestSameColor(t *testing.T) {
+	tests := []struct {
+		code, vehicle string
+		want          bool
+	}{
+		{"W09M", "W09M", true},
+		{"W09M", "w09m ", true},
+		{"57A", "57A", true},
+		{"57", "57A", true},   // The EPC dropped the variant
+		{"57A", "57", true},   // The van's code has none
+		{"57A", "57B", false}, // Different variants
+		{"W0", "W09M", false}, // A prefix isn't the same code
+		{"W09", "W09M", true},
+		{"W09M", "W091", false},
+		{"A", "AB", false},
+		{"", "W09M", false},
+		{"W09M", "", false},
+	}
+	for _, tt := range tests {
+		if got := sameColor(tt.code, tt.vehicle); got != tt.want {
+			t.Errorf("sameColor(%q, %q) = %v, want %v", tt.code, tt.vehicle, got, tt.want)
+		}
+	}
+}
+
+func TestSplitVariant(t *testing.T) {
+	tests := []struct {
+		code, base, variant string
+	}{
+		{"W09M", "W09", "M"},
+		{"57A", "57", "A"},
+		{"57", "57", ""},
+		{"AC", "AC", ""},
+		{"M", "M", ""},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		base, variant := splitVariant(tt.code)
+		if base != tt.base || variant != tt.variant {
+			t.Errorf("splitVariant(%q) = %q, %q, want %q, %q", tt.code, base, variant, tt.base, tt.variant)
+		}
+	}
+}
+
+func TestColorMatch(t *testing.T) {
+	van := Vehicle{Exterior: "W09M", Interior: "57A"}
+	tests := []struct {
+		color     string
+		wantCode  string
+		wantWhich string
+	}{
+		{"W09M", "W09M", "exterior"},
+		{"R10, 57", "57", "interior"},
+		{"r10/w09", "W09", "exterior"},
+		{"R10", "", ""},
+	}
+	for _, tt := range tests {
+		code, which := van.ColorMatch(Part{Color: &tt.color})
+		if code != tt.wantCode || which != tt.wantWhich {
+			t.Errorf("ColorMatch(%q) = %q, %q, want %q, %q", tt.color, code, which, tt.wantCode, tt.wantWhich)
+		}
+	}
+	if code, which := van.ColorMatch(Part{}); code != "" || which != "" {
+		t.Errorf("ColorMatch(no color) = %q, %q, want none", code, which)
+	}
+}
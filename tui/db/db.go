@@ -3,6 +3,8 @@ package db
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 
 	"zombiezen.com/go/sqlite"
 	"zombiezen.com/go/sqlite/sqlitex"
@@ -10,6 +12,11 @@ import (
 
 type DB struct {
 	conn *sqlite.Conn
+
+	// Spelling correction index, built on first use
+	fuzzyOnce sync.Once
+	fuzzy     *fuzzyIndex
+	fuzzyErr  error
 }
 
 func Open(path string) (*DB, error) {
@@ -33,23 +40,167 @@ func Open(path string) (*DB, error) {
 	}
 
 	// Ensure notes table exists
+	err = sqlitex.ExecuteTransient(conn, "CREATE TABLE IF NOT EXISTS notes "+notesSchema, nil)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("create notes table: %w", err)
+	}
+	if err := migrateNotes(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("migrate notes table: %w", err)
+	}
+
+	// Ensure search history table exists
+	err = sqlitex.ExecuteTransient(conn, `
+		CREATE TABLE IF NOT EXISTS search_history (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			query TEXT NOT NULL UNIQUE,
+			searched_at TEXT DEFAULT CURRENT_TIMESTAMP
+		)
+	`, nil)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("create search history table: %w", err)
+	}
+
+	// Ensure saved searches table exists
+	err = sqlitex.ExecuteTransient(conn, `
+		CREATE TABLE IF NOT EXISTS saved_searches (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE,
+			query TEXT NOT NULL,
+			filter TEXT NOT NULL DEFAULT '{}',
+			created_at TEXT DEFAULT CURRENT_TIMESTAMP
+		)
+	`, nil)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("create saved searches table: %w", err)
+	}
+
+	// Ensure attachments table exists
+	err = sqlitex.ExecuteTransient(conn, `
+		CREATE TABLE IF NOT EXISTS attachments (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			part_number TEXT NOT NULL,
+			name TEXT NOT NULL,
+			path TEXT NOT NULL,
+			size INTEGER NOT NULL DEFAULT 0,
+			created_at TEXT DEFAULT CURRENT_TIMESTAMP
+		)
+	`, nil)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("create attachments table: %w", err)
+	}
+
+	// Ensure order list table exists
 	err = sqlitex.ExecuteTransient(conn, `
-		CREATE TABLE IF NOT EXISTS notes (
+		CREATE TABLE IF NOT EXISTS order_items (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			part_id INTEGER NOT NULL UNIQUE,
-			content TEXT NOT NULL,
-			created_at TEXT DEFAULT CURRENT_TIMESTAMP,
-			updated_at TEXT DEFAULT CURRENT_TIMESTAMP
+			created_at TEXT DEFAULT CURRENT_TIMESTAMP
 		)
 	`, nil)
 	if err != nil {
 		conn.Close()
-		return nil, fmt.Errorf("create notes table: %w", err)
+		return nil, fmt.Errorf("create order items table: %w", err)
+	}
+
+	if err := ensureNotesFTS(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("create notes index: %w", err)
+	}
+
+	// Parse spec and notes into the attributes table
+	if err := ensureAttributes(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("index part attributes: %w", err)
 	}
 
 	return &DB{conn: conn}, nil
 }
 
+// notesSchema defines the notes journal: any number of entries per part
+const notesSchema = `(
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	part_id INTEGER NOT NULL,
+	content TEXT NOT NULL,
+	author TEXT NOT NULL DEFAULT '',
+	tags TEXT NOT NULL DEFAULT '',
+	created_at TEXT DEFAULT CURRENT_TIMESTAMP,
+	updated_at TEXT DEFAULT CURRENT_TIMESTAMP
+)`
+
+// migrateNotes converts the original one-note-per-part table into the
+// journal schema. Existing notes keep their IDs and become the first entry
+// for their part.
+func migrateNotes(conn *sqlite.Conn) (err error) {
+	var schema string
+	err = sqlitex.Execute(conn, "SELECT sql FROM sqlite_master WHERE type = 'table' AND name = 'notes'", &sqlitex.ExecOptions{
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			schema = stmt.ColumnText(0)
+			return nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(schema, "UNIQUE") {
+		return sqlitex.ExecuteTransient(conn, "CREATE INDEX IF NOT EXISTS notes_part_id ON notes(part_id)", nil)
+	}
+
+	defer sqlitex.Save(conn)(&err)
+	return sqlitex.ExecuteScript(conn, `
+		CREATE TABLE notes_journal `+notesSchema+`;
+		INSERT INTO notes_journal (id, part_id, content, created_at, updated_at)
+			SELECT id, part_id, content, created_at, updated_at FROM notes;
+		DROP TABLE notes;
+		ALTER TABLE notes_journal RENAME TO notes;
+		CREATE INDEX notes_part_id ON notes(part_id);
+	`, nil)
+}
+
+// ensureNotesFTS creates the full-text index over note contents, with
+// triggers that keep it in sync, and fills it the first time
+func ensureNotesFTS(conn *sqlite.Conn) error {
+	exists, err := tableExists(conn, "notes_fts")
+	if err != nil {
+		return err
+	}
+
+	err = sqlitex.ExecuteScript(conn, `
+		CREATE VIRTUAL TABLE IF NOT EXISTS notes_fts USING fts5(content, content='notes', content_rowid='id');
+
+		CREATE TRIGGER IF NOT EXISTS notes_fts_insert AFTER INSERT ON notes BEGIN
+			INSERT INTO notes_fts(rowid, content) VALUES (new.id, new.content);
+		END;
+		CREATE TRIGGER IF NOT EXISTS notes_fts_delete AFTER DELETE ON notes BEGIN
+			INSERT INTO notes_fts(notes_fts, rowid, content) VALUES ('delete', old.id, old.content);
+		END;
+		CREATE TRIGGER IF NOT EXISTS notes_fts_update AFTER UPDATE ON notes BEGIN
+			INSERT INTO notes_fts(notes_fts, rowid, content) VALUES ('delete', old.id, old.content);
+			INSERT INTO notes_fts(rowid, content) VALUES (new.id, new.content);
+		END;
+	`, nil)
+	if err != nil || exists {
+		return err
+	}
+	return sqlitex.ExecuteTransient(conn, "INSERT INTO notes_fts(notes_fts) VALUES ('rebuild')", nil)
+}
+
+func tableExists(conn *sqlite.Conn, name string) (bool, error) {
+	var found bool
+	err := sqlitex.Execute(conn, "SELECT 1 FROM sqlite_master WHERE name = ?", &sqlitex.ExecOptions{
+		Args: []any{name},
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			found = true
+			return nil
+		},
+	})
+	return found, err
+}
+
 func (d *DB) Close() error {
 	return d.conn.Close()
 }
@@ -136,6 +287,45 @@ func (d *DB) GetPartsForSubgroup(subgroupID string) ([]PartWithDiagram, error) {
 	return parts, err
 }
 
+func (d *DB) GetTag(id string) (*Tag, error) {
+	var tag *Tag
+	err := sqlitex.Execute(d.conn, "SELECT id, name, category FROM tags WHERE id = ?", &sqlitex.ExecOptions{
+		Args: []any{id},
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			tag = &Tag{
+				ID:       stmt.ColumnText(0),
+				Name:     stmt.ColumnText(1),
+				Category: stmt.ColumnText(2),
+			}
+			return nil
+		},
+	})
+	return tag, err
+}
+
+// GetPartsForTag returns the parts with a tag, diagram by diagram
+func (d *DB) GetPartsForTag(tagID string) ([]PartWithDiagram, error) {
+	var parts []PartWithDiagram
+	err := sqlitex.Execute(d.conn, `
+		SELECT p.id, p.detail_page_id, p.part_number, p.pnc, p.description,
+			   p.ref_number, p.quantity, p.spec, p.notes, p.color,
+			   p.model_date_range, p.diagram_id, p.group_id, p.subgroup_id,
+			   p.replacement_part_number, d.image_path
+		FROM parts p
+		JOIN diagrams d ON p.diagram_id = d.id
+		JOIN tags_to_parts tp ON tp.part_id = p.id
+		WHERE tp.tag_id = ?
+		ORDER BY p.group_id, p.subgroup_id, p.diagram_id, p.ref_number, p.part_number
+	`, &sqlitex.ExecOptions{
+		Args: []any{tagID},
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			parts = append(parts, scanPartWithDiagram(stmt))
+			return nil
+		},
+	})
+	return parts, err
+}
+
 func (d *DB) GetDiagramForSubgroup(subgroupID string) (*Diagram, error) {
 	var diagram *Diagram
 	err := sqlitex.Execute(d.conn, "SELECT id, group_id, subgroup_id, name, image_url, image_path, source_url FROM diagrams WHERE subgroup_id = ? LIMIT 1", &sqlitex.ExecOptions{
@@ -197,37 +387,18 @@ func (d *DB) GetPart(id int) (*PartWithDiagram, error) {
 	return part, err
 }
 
-func (d *DB) SearchParts(query string) ([]SearchResult, error) {
-	if query == "" {
-		return nil, nil
-	}
-	var results []SearchResult
-	err := sqlitex.Execute(d.conn, `
-		SELECT p.id, p.detail_page_id, p.part_number, p.pnc, p.description,
-			   p.ref_number, p.quantity, p.spec, p.notes, p.color,
-			   p.model_date_range, p.diagram_id, p.group_id, p.subgroup_id,
-			   p.replacement_part_number, d.image_path,
-			   g.name, s.name
-		FROM parts p
-		JOIN parts_fts fts ON p.id = fts.rowid
-		JOIN diagrams d ON p.diagram_id = d.id
-		JOIN groups g ON p.group_id = g.id
-		LEFT JOIN subgroups s ON p.subgroup_id = s.id
-		WHERE parts_fts MATCH ?
-		ORDER BY rank
-		LIMIT 50
-	`, &sqlitex.ExecOptions{
-		Args: []any{query + "*"},
+// FindPartID returns the ID of the first part with a part number, ignoring
+// case, or 0 if there is none
+func (d *DB) FindPartID(partNumber string) (int, error) {
+	var id int
+	err := sqlitex.Execute(d.conn, "SELECT id FROM parts WHERE part_number = ? COLLATE NOCASE ORDER BY id LIMIT 1", &sqlitex.ExecOptions{
+		Args: []any{partNumber},
 		ResultFunc: func(stmt *sqlite.Stmt) error {
-			results = append(results, SearchResult{
-				PartWithDiagram: scanPartWithDiagram(stmt),
-				GroupName:       stmt.ColumnText(16),
-				SubgroupName:    nullableString(stmt, 17),
-			})
+			id = stmt.ColumnInt(0)
 			return nil
 		},
 	})
-	return results, err
+	return id, err
 }
 
 func (d *DB) AddBookmark(partID int) error {
@@ -294,44 +465,71 @@ func (d *DB) GetBookmarkCount() (int, error) {
 	return count, err
 }
 
-func (d *DB) SetNote(partID int, content string) error {
+// AddNote adds a journal entry to a part and returns its ID
+func (d *DB) AddNote(partID int, content, author string, tags []string) (int, error) {
+	err := sqlitex.ExecuteTransient(d.conn, "INSERT INTO notes (part_id, content, author, tags) VALUES (?, ?, ?, ?)", &sqlitex.ExecOptions{
+		Args: []any{partID, content, author, strings.Join(tags, ",")},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(d.conn.LastInsertRowID()), nil
+}
+
+// UpdateNote replaces the contents and tags of a journal entry
+func (d *DB) UpdateNote(id int, content string, tags []string) error {
 	return sqlitex.ExecuteTransient(d.conn, `
-		INSERT INTO notes (part_id, content) VALUES (?, ?)
-		ON CONFLICT(part_id) DO UPDATE SET content = ?, updated_at = CURRENT_TIMESTAMP
+		UPDATE notes SET content = ?, tags = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?
 	`, &sqlitex.ExecOptions{
-		Args: []any{partID, content, content},
+		Args: []any{content, strings.Join(tags, ","), id},
 	})
 }
 
-func (d *DB) RemoveNote(partID int) error {
-	return sqlitex.ExecuteTransient(d.conn, "DELETE FROM notes WHERE part_id = ?", &sqlitex.ExecOptions{
-		Args: []any{partID},
+func (d *DB) RemoveNote(id int) error {
+	return sqlitex.ExecuteTransient(d.conn, "DELETE FROM notes WHERE id = ?", &sqlitex.ExecOptions{
+		Args: []any{id},
 	})
 }
 
-func (d *DB) GetNote(partID int) (*string, error) {
-	var content *string
-	err := sqlitex.Execute(d.conn, "SELECT content FROM notes WHERE part_id = ?", &sqlitex.ExecOptions{
+// GetPartNotes returns a part's journal entries, oldest first
+func (d *DB) GetPartNotes(partID int) ([]Note, error) {
+	var notes []Note
+	err := sqlitex.Execute(d.conn, `
+		SELECT id, part_id, content, author, tags, created_at, updated_at
+		FROM notes
+		WHERE part_id = ?
+		ORDER BY created_at, id
+	`, &sqlitex.ExecOptions{
 		Args: []any{partID},
 		ResultFunc: func(stmt *sqlite.Stmt) error {
-			c := stmt.ColumnText(0)
-			content = &c
+			notes = append(notes, Note{
+				ID:        stmt.ColumnInt(0),
+				PartID:    stmt.ColumnInt(1),
+				Content:   stmt.ColumnText(2),
+				Author:    stmt.ColumnText(3),
+				Tags:      splitTags(stmt.ColumnText(4)),
+				CreatedAt: stmt.ColumnText(5),
+				UpdatedAt: stmt.ColumnText(6),
+			})
 			return nil
 		},
 	})
-	return content, err
+	return notes, err
 }
 
+// GetNotes returns the latest journal entry for each part with notes
 func (d *DB) GetNotes() ([]NoteResult, error) {
 	var notes []NoteResult
 	err := sqlitex.Execute(d.conn, `
 		SELECT n.id, n.part_id, n.content, n.updated_at,
 			   p.part_number, p.pnc, p.description,
-			   g.name, s.name
+			   g.name, s.name,
+			   (SELECT COUNT(*) FROM notes c WHERE c.part_id = n.part_id)
 		FROM notes n
 		JOIN parts p ON n.part_id = p.id
 		JOIN groups g ON p.group_id = g.id
 		LEFT JOIN subgroups s ON p.subgroup_id = s.id
+		WHERE n.id = (SELECT l.id FROM notes l WHERE l.part_id = n.part_id ORDER BY l.updated_at DESC, l.id DESC LIMIT 1)
 		ORDER BY n.updated_at DESC
 	`, &sqlitex.ExecOptions{
 		ResultFunc: func(stmt *sqlite.Stmt) error {
@@ -345,6 +543,7 @@ func (d *DB) GetNotes() ([]NoteResult, error) {
 				Description:  nullableString(stmt, 6),
 				GroupName:    stmt.ColumnText(7),
 				SubgroupName: nullableString(stmt, 8),
+				Count:        stmt.ColumnInt(9),
 			})
 			return nil
 		},
@@ -354,7 +553,7 @@ func (d *DB) GetNotes() ([]NoteResult, error) {
 
 func (d *DB) GetNoteCount() (int, error) {
 	var count int
-	err := sqlitex.Execute(d.conn, "SELECT COUNT(*) FROM notes", &sqlitex.ExecOptions{
+	err := sqlitex.Execute(d.conn, "SELECT COUNT(DISTINCT part_id) FROM notes", &sqlitex.ExecOptions{
 		ResultFunc: func(stmt *sqlite.Stmt) error {
 			count = stmt.ColumnInt(0)
 			return nil
@@ -365,6 +564,30 @@ func (d *DB) GetNoteCount() (int, error) {
 
 // Helper functions
 
+// matchExpr turns free text into an FTS5 query: each word is quoted so
+// punctuation can't break the syntax, and the last word matches as a prefix
+func matchExpr(query string) string {
+	words := tokenize(query)
+	if len(words) == 0 {
+		return ""
+	}
+	for i, w := range words {
+		words[i] = `"` + w + `"`
+	}
+	return strings.Join(words, " ") + "*"
+}
+
+// splitTags parses a comma-separated tag list
+func splitTags(s string) []string {
+	var tags []string
+	for _, t := range strings.Split(s, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
 func nullableString(stmt *sqlite.Stmt, col int) *string {
 	if stmt.ColumnType(col) == sqlite.TypeNull {
 		return nil
@@ -428,5 +651,28 @@ func (d *DB) GetSubgroupsForPartNumber(partNumber string) ([]SubgroupWithGroup,
 	return subgroups, err
 }
 
+// GetPartsForPartNumber returns every catalog entry for a part number, one
+// per diagram it appears on
+func (d *DB) GetPartsForPartNumber(partNumber string) ([]PartWithDiagram, error) {
+	var parts []PartWithDiagram
+	err := sqlitex.Execute(d.conn, `
+		SELECT p.id, p.detail_page_id, p.part_number, p.pnc, p.description,
+			   p.ref_number, p.quantity, p.spec, p.notes, p.color,
+			   p.model_date_range, p.diagram_id, p.group_id, p.subgroup_id,
+			   p.replacement_part_number, d.image_path
+		FROM parts p
+		JOIN diagrams d ON p.diagram_id = d.id
+		WHERE p.part_number = ?
+		ORDER BY p.id
+	`, &sqlitex.ExecOptions{
+		Args: []any{partNumber},
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			parts = append(parts, scanPartWithDiagram(stmt))
+			return nil
+		},
+	})
+	return parts, err
+}
+
 // Unused import guard
 var _ = context.Background
@@ -0,0 +1,76 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+
+	"zombiezen.com/go/sqlite"
+	"zombiezen.com/go/sqlite/sqlitex"
+)
+
+// catalogSchema is the part of the scraper's schema the TUI reads
+const catalogSchema = `
+	CREATE TABLE groups (id TEXT PRIMARY KEY, name TEXT NOT NULL);
+	CREATE TABLE subgroups (id TEXT PRIMARY KEY, name TEXT NOT NULL, group_id TEXT NOT NULL, path TEXT NOT NULL);
+	CREATE TABLE diagrams (id TEXT PRIMARY KEY, group_id TEXT NOT NULL, subgroup_id TEXT, name TEXT NOT NULL,
+		image_url TEXT, image_path TEXT, source_url TEXT NOT NULL);
+	CREATE TABLE parts (id INTEGER PRIMARY KEY AUTOINCREMENT, detail_page_id TEXT, part_number TEXT NOT NULL,
+		pnc TEXT, description TEXT, ref_number TEXT, quantity INTEGER, spec TEXT, notes TEXT, color TEXT,
+		model_date_range TEXT, diagram_id TEXT NOT NULL, group_id TEXT NOT NULL, subgroup_id TEXT,
+		replacement_part_number TEXT, search_terms TEXT, UNIQUE(part_number, diagram_id));
+	CREATE TABLE tags (id TEXT PRIMARY KEY, name TEXT NOT NULL, category TEXT NOT NULL);
+	CREATE TABLE tags_to_parts (tag_id TEXT NOT NULL, part_id INTEGER NOT NULL, PRIMARY KEY (tag_id, part_id));
+	CREATE VIRTUAL TABLE parts_fts USING fts5(part_number, description, search_terms,
+		content='parts', content_rowid='id');
+	CREATE TRIGGER parts_ai AFTER INSERT ON parts BEGIN
+		INSERT INTO parts_fts(rowid, part_number, description, search_terms)
+		VALUES (new.id, new.part_number, new.description, new.search_terms);
+	END;
+`
+
+// createCatalog makes a database file with the catalog schema, then runs
+// setup against it, returning its path
+func createCatalog(t *testing.T, setup string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "delica.db")
+	conn, err := sqlite.OpenConn(path, sqlite.OpenReadWrite, sqlite.OpenCreate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if err := sqlitex.ExecuteScript(conn, catalogSchema+setup, nil); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// openCatalog opens a catalog built by setup, as the TUI does
+func openCatalog(t *testing.T, setup string) *DB {
+	t.Helper()
+	d, err := Open(createCatalog(t, setup))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { d.Close() })
+	return d
+}
+
+func TestMatchExpr(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", ""},
+		{"  ", ""},
+		{"gasket", `"GASKET"*`},
+		{"head gasket", `"HEAD" "GASKET"*`},
+		{"MB-123456", `"MB" "123456"*`},
+		{`gasket" OR "x`, `"GASKET" "OR" "X"*`},
+		{"water/pump,", `"WATER" "PUMP"*`},
+	}
+	for _, tt := range tests {
+		if got := matchExpr(tt.query); got != tt.want {
+			t.Errorf("matchExpr(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package db
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"zombiezen.com/go/sqlite"
+	"zombiezen.com/go/sqlite/sqlitex"
+)
+
+// fuzzyThreshold is the number of exact hits below which a search also
+// looks for spelling corrections
+const fuzzyThreshold = 5
+
+// fuzzyIndex maps trigrams of catalog words (description words and part
+// numbers) to the words containing them, so that misspelled search terms
+// can be matched to words that actually occur in the catalog.
+type fuzzyIndex struct {
+	words    []string         // Sorted, uppercase
+	counts   []int            // Occurrences of each word, for tie-breaking
+	trigrams map[string][]int // Trigram -> indexes into words
+}
+
+// buildFuzzyIndex reads every part number and description word into a trigram index
+func (d *DB) buildFuzzyIndex() (*fuzzyIndex, error) {
+	seen := make(map[string]int)
+	err := sqlitex.Execute(d.conn, "SELECT part_number, description FROM parts", &sqlitex.ExecOptions{
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			for _, w := range tokenize(stmt.ColumnText(0) + " " + stmt.ColumnText(1)) {
+				if len(w) >= 3 {
+					seen[w]++
+				}
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	idx := &fuzzyIndex{trigrams: make(map[string][]int)}
+	for w := range seen {
+		idx.words = append(idx.words, w)
+	}
+	sort.Strings(idx.words)
+
+	for i, w := range idx.words {
+		idx.counts = append(idx.counts, seen[w])
+		for _, t := range trigrams(w) {
+			idx.trigrams[t] = append(idx.trigrams[t], i)
+		}
+	}
+	return idx, nil
+}
+
+// hasPrefix reports whether any catalog word starts with prefix
+func (idx *fuzzyIndex) hasPrefix(prefix string) bool {
+	i := sort.SearchStrings(idx.words, prefix)
+	return i < len(idx.words) && strings.HasPrefix(idx.words[i], prefix)
+}
+
+// correct returns the catalog word closest to token, or "" if nothing is
+// close enough. Tokens are compared against whole words and word prefixes,
+// so a partly typed word with a typo still finds its match.
+func (idx *fuzzyIndex) correct(token string) string {
+	maxDist := 1
+	if len([]rune(token)) > 6 {
+		maxDist = 2
+	}
+
+	// Count shared trigrams to pick candidates worth measuring
+	shared := make(map[int]int)
+	for _, t := range trigrams(token) {
+		for _, i := range idx.trigrams[t] {
+			shared[i]++
+		}
+	}
+
+	best := ""
+	bestDist := maxDist + 1
+	bestCount := 0
+	for i := range shared {
+		w := idx.words[i]
+		dist := editDistance(token, w)
+		if p := prefixOf(w, len([]rune(token))); p != w {
+			if pd := editDistance(token, p); pd < dist {
+				dist = pd
+			}
+		}
+		if dist < bestDist || dist == bestDist && idx.counts[i] > bestCount {
+			best, bestDist, bestCount = w, dist, idx.counts[i]
+		}
+	}
+
+	if bestDist > maxDist {
+		return ""
+	}
+	return best
+}
+
+// Suggest returns a corrected version of query with misspelled words replaced
+// by the closest catalog words, or "" when every word is already known.
+// The index is built on first use.
+func (d *DB) Suggest(query string) (string, error) {
+	d.fuzzyOnce.Do(func() {
+		d.fuzzy, d.fuzzyErr = d.buildFuzzyIndex()
+	})
+	if d.fuzzyErr != nil {
+		return "", d.fuzzyErr
+	}
+
+	tokens := tokenize(query)
+	changed := false
+	for i, t := range tokens {
+		// Short tokens match too much to correct reliably
+		if len(t) < 4 || d.fuzzy.hasPrefix(t) {
+			continue
+		}
+		if c := d.fuzzy.correct(t); c != "" {
+			tokens[i] = c
+			changed = true
+		}
+	}
+
+	if !changed {
+		return "", nil
+	}
+	return strings.Join(tokens, " "), nil
+}
+
+// tokenize splits text into uppercase alphanumeric words
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToUpper(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// trigrams returns the three-character substrings of a word, padded so that
+// the first and last characters carry weight
+func trigrams(word string) []string {
+	r := []rune(" " + word + " ")
+	var out []string
+	for i := 0; i+3 <= len(r); i++ {
+		out = append(out, string(r[i:i+3]))
+	}
+	return out
+}
+
+// prefixOf returns the first n runes of s
+func prefixOf(s string, n int) string {
+	r := []rune(s)
+	if n >= len(r) {
+		return s
+	}
+	return string(r[:n])
+}
+
+// editDistance is the optimal string alignment distance between a and b:
+// insertions, deletions, substitutions and adjacent transpositions each cost 1
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(rb)]
+}
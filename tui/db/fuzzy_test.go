@@ -0,0 +1,63 @@
+package db
+
+import "testing"
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"GASKET", "GASKET", 0},
+		{"", "PUMP", 4},
+		{"PUMP", "", 4},
+		{"GASKET", "GASKIT", 1},  // Substitution
+		{"GASKET", "GASKETS", 1}, // Insertion
+		{"GASKET", "GASKT", 1},   // Deletion
+		{"GASKET", "GASKTE", 1},  // Adjacent transposition
+		{"GASKET", "AGSKTE", 2},
+		{"CA", "ABC", 3}, // Optimal string alignment doesn't edit a substring twice
+		{"BOLT", "NUT", 3},
+		{"ÖL", "OL", 1}, // Runes, not bytes
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	d := openCatalog(t, `
+		INSERT INTO parts (part_number, description, diagram_id, group_id) VALUES
+			('MD050123', 'GASKET,WATER PUMP', 'd1', 'engine'),
+			('MD123456', 'GASKET,CYLINDER HEAD', 'd1', 'engine'),
+			('MD654321', 'THERMOSTAT', 'd1', 'engine'),
+			('MD654322', 'THERMOSTAT HOUSING', 'd1', 'engine');
+	`)
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"gasket", ""},       // Known word
+		{"gask", ""},         // Prefix of a known word
+		{"gaskte", "GASKET"}, // Transposed letters
+		{"cylnder head", "CYLINDER HEAD"},
+		{"themrostat", "THERMOSTAT"}, // Longer words allow two edits
+		{"housnig", "HOUSING"},
+		{"pmp", ""},       // Too short to correct
+		{"xylophone", ""}, // Nothing close
+		{"md65432", ""},   // Part number prefix
+		{"md05o123", "MD050123"},
+	}
+	for _, tt := range tests {
+		got, err := d.Suggest(tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("Suggest(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
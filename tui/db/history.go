@@ -0,0 +1,95 @@
+package db
+
+import (
+	"encoding/json"
+	"strings"
+
+	"zombiezen.com/go/sqlite"
+	"zombiezen.com/go/sqlite/sqlitex"
+)
+
+// historyLimit caps how many recent queries are kept
+const historyLimit = 50
+
+// AddSearchHistory records a query as the most recent search
+func (d *DB) AddSearchHistory(query string) error {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+
+	// Replacing the row gives it a new, highest id
+	err := sqlitex.ExecuteTransient(d.conn, "INSERT OR REPLACE INTO search_history (query) VALUES (?)", &sqlitex.ExecOptions{
+		Args: []any{query},
+	})
+	if err != nil {
+		return err
+	}
+	return sqlitex.ExecuteTransient(d.conn, `
+		DELETE FROM search_history
+		WHERE id NOT IN (SELECT id FROM search_history ORDER BY id DESC LIMIT ?)
+	`, &sqlitex.ExecOptions{
+		Args: []any{historyLimit},
+	})
+}
+
+// GetSearchHistory returns recent queries, newest first
+func (d *DB) GetSearchHistory() ([]string, error) {
+	var queries []string
+	err := sqlitex.Execute(d.conn, "SELECT query FROM search_history ORDER BY id DESC", &sqlitex.ExecOptions{
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			queries = append(queries, stmt.ColumnText(0))
+			return nil
+		},
+	})
+	return queries, err
+}
+
+// SaveSearch stores a query and its filters under a name, replacing any
+// saved search with the same name
+func (d *DB) SaveSearch(name, query string, filter SearchFilter) error {
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return err
+	}
+	return sqlitex.ExecuteTransient(d.conn, `
+		INSERT INTO saved_searches (name, query, filter) VALUES (?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET query = excluded.query, filter = excluded.filter
+	`, &sqlitex.ExecOptions{
+		Args: []any{name, query, string(data)},
+	})
+}
+
+func (d *DB) RemoveSavedSearch(id int) error {
+	return sqlitex.ExecuteTransient(d.conn, "DELETE FROM saved_searches WHERE id = ?", &sqlitex.ExecOptions{
+		Args: []any{id},
+	})
+}
+
+func (d *DB) GetSavedSearches() ([]SavedSearch, error) {
+	var searches []SavedSearch
+	err := sqlitex.Execute(d.conn, "SELECT id, name, query, filter, created_at FROM saved_searches ORDER BY name", &sqlitex.ExecOptions{
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			s := SavedSearch{
+				ID:        stmt.ColumnInt(0),
+				Name:      stmt.ColumnText(1),
+				Query:     stmt.ColumnText(2),
+				CreatedAt: stmt.ColumnText(4),
+			}
+			// A filter that no longer parses just means an unfiltered search
+			json.Unmarshal([]byte(stmt.ColumnText(3)), &s.Filter)
+			searches = append(searches, s)
+			return nil
+		},
+	})
+	return searches, err
+}
+
+// CountSearch returns the number of parts a filtered search finds
+func (d *DB) CountSearch(query string, filter SearchFilter) (int, error) {
+	match, err := d.ResolveSearch(query, filter)
+	if err != nil || match == nil {
+		return 0, err
+	}
+	return match.total, nil
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"zombiezen.com/go/sqlite"
+	"zombiezen.com/go/sqlite/sqlitex"
+)
+
+func TestMigrateNotes(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup string
+		want  []string // id|part_id|content|author|tags|created_at
+	}{
+		{
+			name: "one note per part",
+			setup: `
+				CREATE TABLE notes (id INTEGER PRIMARY KEY AUTOINCREMENT, part_id INTEGER NOT NULL UNIQUE,
+					content TEXT NOT NULL, created_at TEXT DEFAULT CURRENT_TIMESTAMP,
+					updated_at TEXT DEFAULT CURRENT_TIMESTAMP);
+				INSERT INTO notes (id, part_id, content, created_at) VALUES
+					(3, 10, 'Check the gasket', '2024-01-02 03:04:05'),
+					(7, 11, 'Ordered', '2024-02-03 04:05:06');
+			`,
+			want: []string{
+				"3|10|Check the gasket|||2024-01-02 03:04:05",
+				"7|11|Ordered|||2024-02-03 04:05:06",
+			},
+		},
+		{
+			name: "already a journal",
+			setup: `
+				CREATE TABLE notes ` + notesSchema + `;
+				INSERT INTO notes (id, part_id, content, author, tags, created_at) VALUES
+					(1, 10, 'First', 'sam', 'todo', '2024-01-02 03:04:05'),
+					(2, 10, 'Second', 'sam', '', '2024-01-03 03:04:05');
+			`,
+			want: []string{
+				"1|10|First|sam|todo|2024-01-02 03:04:05",
+				"2|10|Second|sam||2024-01-03 03:04:05",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, err := sqlite.OpenConn(filepath.Join(t.TempDir(), "notes.db"), sqlite.OpenReadWrite, sqlite.OpenCreate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+			if err := sqlitex.ExecuteScript(conn, tt.setup, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := migrateNotes(conn); err != nil {
+				t.Fatalf("migrateNotes: %v", err)
+			}
+			// A second run finds nothing to do
+			if err := migrateNotes(conn); err != nil {
+				t.Fatalf("migrateNotes again: %v", err)
+			}
+
+			var got []string
+			err = sqlitex.Execute(conn, "SELECT id, part_id, content, author, tags, created_at FROM notes ORDER BY id", &sqlitex.ExecOptions{
+				ResultFunc: func(stmt *sqlite.Stmt) error {
+					var row []string
+					for i := range stmt.ColumnCount() {
+						row = append(row, stmt.ColumnText(i))
+					}
+					got = append(got, strings.Join(row, "|"))
+					return nil
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("notes = %q, want %q", got, tt.want)
+			}
+
+			// Parts can now have more than one entry
+			err = sqlitex.Execute(conn, "INSERT INTO notes (part_id, content) VALUES (10, 'Another')", nil)
+			if err != nil {
+				t.Errorf("adding a second entry: %v", err)
+			}
+			if exists, _ := tableExists(conn, "notes_part_id"); !exists {
+				t.Errorf("no notes_part_id index")
+			}
+		})
+	}
+}
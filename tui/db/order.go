@@ -0,0 +1,73 @@
+package db
+
+import (
+	"zombiezen.com/go/sqlite"
+	"zombiezen.com/go/sqlite/sqlitex"
+)
+
+// AddToOrder puts a part on the order list, if it isn't there already
+func (d *DB) AddToOrder(partID int) error {
+	return sqlitex.ExecuteTransient(d.conn, "INSERT OR IGNORE INTO order_items (part_id) VALUES (?)", &sqlitex.ExecOptions{
+		Args: []any{partID},
+	})
+}
+
+func (d *DB) RemoveFromOrder(partID int) error {
+	return sqlitex.ExecuteTransient(d.conn, "DELETE FROM order_items WHERE part_id = ?", &sqlitex.ExecOptions{
+		Args: []any{partID},
+	})
+}
+
+// IsInOrder reports whether a part is on the order list
+func (d *DB) IsInOrder(partID int) (bool, error) {
+	var found bool
+	err := sqlitex.Execute(d.conn, "SELECT 1 FROM order_items WHERE part_id = ?", &sqlitex.ExecOptions{
+		Args: []any{partID},
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			found = true
+			return nil
+		},
+	})
+	return found, err
+}
+
+// GetOrderItems returns the order list in the order parts were added
+func (d *DB) GetOrderItems() ([]OrderItem, error) {
+	var items []OrderItem
+	err := sqlitex.Execute(d.conn, `
+		SELECT o.id, o.part_id, o.created_at,
+			   p.part_number, p.pnc, p.description,
+			   g.name, s.name
+		FROM order_items o
+		JOIN parts p ON o.part_id = p.id
+		JOIN groups g ON p.group_id = g.id
+		LEFT JOIN subgroups s ON p.subgroup_id = s.id
+		ORDER BY o.id
+	`, &sqlitex.ExecOptions{
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			items = append(items, OrderItem{
+				ID:           stmt.ColumnInt(0),
+				PartID:       stmt.ColumnInt(1),
+				CreatedAt:    stmt.ColumnText(2),
+				PartNumber:   stmt.ColumnText(3),
+				PNC:          nullableString(stmt, 4),
+				Description:  nullableString(stmt, 5),
+				GroupName:    stmt.ColumnText(6),
+				SubgroupName: nullableString(stmt, 7),
+			})
+			return nil
+		},
+	})
+	return items, err
+}
+
+func (d *DB) GetOrderCount() (int, error) {
+	var count int
+	err := sqlitex.Execute(d.conn, "SELECT COUNT(*) FROM order_items", &sqlitex.ExecOptions{
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			count = stmt.ColumnInt(0)
+			return nil
+		},
+	})
+	return count, err
+}
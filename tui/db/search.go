@@ -0,0 +1,281 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"zombiezen.com/go/sqlite"
+	"zombiezen.com/go/sqlite/sqlitex"
+)
+
+// facetLimit caps how many values are listed for each facet kind
+const facetLimit = 8
+
+// SearchFilter narrows search results to parts matching every set field.
+type SearchFilter struct {
+	GroupID        string
+	SubgroupID     string
+	TagID          string
+	Color          string
+	Attribute      string // Attribute key, see Attribute.Key
+	HasReplacement bool
+	Bookmarked     bool
+}
+
+// IsZero reports whether no filter is set
+func (f SearchFilter) IsZero() bool {
+	return f == SearchFilter{}
+}
+
+// Toggle sets the filter for a facet, or clears it if it is already set.
+func (f *SearchFilter) Toggle(facet Facet) {
+	toggle := func(field *string) {
+		if *field == facet.Value {
+			*field = ""
+		} else {
+			*field = facet.Value
+		}
+	}
+
+	switch facet.Kind {
+	case FacetGroup:
+		toggle(&f.GroupID)
+	case FacetSubgroup:
+		toggle(&f.SubgroupID)
+	case FacetTag:
+		toggle(&f.TagID)
+	case FacetColor:
+		toggle(&f.Color)
+	case FacetAttribute:
+		toggle(&f.Attribute)
+	case FacetReplacement:
+		f.HasReplacement = !f.HasReplacement
+	case FacetBookmarked:
+		f.Bookmarked = !f.Bookmarked
+	}
+}
+
+// Active reports whether the filter is set to a facet's value.
+func (f SearchFilter) Active(facet Facet) bool {
+	switch facet.Kind {
+	case FacetGroup:
+		return f.GroupID == facet.Value
+	case FacetSubgroup:
+		return f.SubgroupID == facet.Value
+	case FacetTag:
+		return f.TagID == facet.Value
+	case FacetColor:
+		return f.Color == facet.Value
+	case FacetAttribute:
+		return f.Attribute == facet.Value
+	case FacetReplacement:
+		return f.HasReplacement
+	case FacetBookmarked:
+		return f.Bookmarked
+	}
+	return false
+}
+
+// where returns SQL conditions (joined with AND) and their args
+func (f SearchFilter) where() (string, []any) {
+	conds := []string{"1"}
+	var args []any
+
+	if f.GroupID != "" {
+		conds = append(conds, "p.group_id = ?")
+		args = append(args, f.GroupID)
+	}
+	if f.SubgroupID != "" {
+		conds = append(conds, "p.subgroup_id = ?")
+		args = append(args, f.SubgroupID)
+	}
+	if f.TagID != "" {
+		conds = append(conds, "p.id IN (SELECT part_id FROM tags_to_parts WHERE tag_id = ?)")
+		args = append(args, f.TagID)
+	}
+	if f.Color != "" {
+		conds = append(conds, "p.color = ?")
+		args = append(args, f.Color)
+	}
+	if a, ok := ParseAttributeKey(f.Attribute); ok {
+		conds = append(conds, "p.id IN (SELECT part_id FROM part_attributes WHERE kind = ? AND value = ?)")
+		args = append(args, a.Kind, a.Value)
+	}
+	if f.HasReplacement {
+		conds = append(conds, "COALESCE(p.replacement_part_number, '') != ''")
+	}
+	if f.Bookmarked {
+		conds = append(conds, "p.id IN (SELECT part_id FROM bookmarks)")
+	}
+
+	return strings.Join(conds, " AND "), args
+}
+
+// SearchMatch is a query resolved for a filter into the FTS expression to
+// search, including any spelling correction. Resolve it once with
+// ResolveSearch and pass it to SearchPartsPage and SearchFacets.
+type SearchMatch struct {
+	expr       string // Expression used to find hits
+	exact      string // Expression for the query as typed
+	suggestion string
+	total      int // Hits for expr, counted while resolving
+}
+
+// hitsSQL selects parts matching an FTS expression in the catalog or in
+// their notes, with the ID of the matching note. The expression is bound
+// twice; see hitsArgs.
+const hitsSQL = `
+	SELECT rowid AS id, rank, NULL AS note_id FROM parts_fts WHERE parts_fts MATCH ?
+	UNION ALL
+	SELECT n.part_id, notes_fts.rank, n.id FROM notes_fts
+	JOIN notes n ON n.id = notes_fts.rowid
+	WHERE notes_fts MATCH ?`
+
+// hitsCTE selects matching part IDs, their best rank, and a matching note if any
+const hitsCTE = `WITH hits AS (SELECT id, MIN(rank) AS rank, MAX(note_id) AS note_id FROM (` + hitsSQL + `) GROUP BY id)`
+
+// hitsArgs returns the arguments for hitsSQL or hitsCTE
+func hitsArgs(expr string) []any {
+	return []any{expr, expr}
+}
+
+// hitsFrom joins hits to parts and the tables needed for display and filtering
+const hitsFrom = `
+	FROM hits
+	JOIN parts p ON p.id = hits.id
+	JOIN diagrams d ON p.diagram_id = d.id
+	JOIN groups g ON p.group_id = g.id
+	LEFT JOIN subgroups s ON p.subgroup_id = s.id`
+
+// ResolveSearch builds the FTS expression for query. When the query as
+// typed finds few parts, misspelled words are corrected and the corrected
+// query is searched as well. It returns nil for a query with no words.
+func (d *DB) ResolveSearch(query string, filter SearchFilter) (*SearchMatch, error) {
+	exact := matchExpr(query)
+	if exact == "" {
+		return nil, nil
+	}
+	match := &SearchMatch{expr: exact, exact: exact}
+
+	count, err := d.countHits(exact, filter)
+	match.total = count
+	if err != nil || count >= fuzzyThreshold {
+		return match, err
+	}
+
+	suggestion, err := d.Suggest(query)
+	if err != nil || suggestion == "" {
+		return match, err
+	}
+	match.suggestion = suggestion
+	match.expr = fmt.Sprintf("(%s) OR (%s)", exact, matchExpr(suggestion))
+	match.total, err = d.countHits(match.expr, filter)
+	return match, err
+}
+
+func (d *DB) countHits(expr string, filter SearchFilter) (int, error) {
+	where, args := filter.where()
+	var count int
+	err := sqlitex.Execute(d.conn, hitsCTE+" SELECT COUNT(*)"+hitsFrom+" WHERE "+where, &sqlitex.ExecOptions{
+		Args: append(hitsArgs(expr), args...),
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			count = stmt.ColumnInt(0)
+			return nil
+		},
+	})
+	return count, err
+}
+
+// SearchPartsPage returns one page of search results for a match resolved
+// with the same filter, along with the total number of matches. Parts are
+// found by their catalog entries or by the contents of their notes. Hits for
+// the query as typed are listed before hits that only match its spelling
+// correction. A negative limit returns every result.
+func (d *DB) SearchPartsPage(match *SearchMatch, filter SearchFilter, offset, limit int) (*SearchPage, error) {
+	page := &SearchPage{}
+	if match == nil {
+		return page, nil
+	}
+	page.Suggestion = match.suggestion
+	page.Total = match.total
+
+	where, args := filter.where()
+	queryArgs := append(hitsArgs(match.expr), hitsArgs(match.exact)...)
+	queryArgs = append(queryArgs, args...)
+	queryArgs = append(queryArgs, limit, offset)
+
+	err := sqlitex.Execute(d.conn, hitsCTE+`
+		SELECT p.id, p.detail_page_id, p.part_number, p.pnc, p.description,
+			   p.ref_number, p.quantity, p.spec, p.notes, p.color,
+			   p.model_date_range, p.diagram_id, p.group_id, p.subgroup_id,
+			   p.replacement_part_number, d.image_path,
+			   g.name, s.name,
+			   hits.id NOT IN (SELECT id FROM (`+hitsSQL+`)) AS fuzzy,
+			   hn.content`+
+		hitsFrom+`
+		LEFT JOIN notes hn ON hn.id = hits.note_id
+		WHERE `+where+`
+		ORDER BY fuzzy, hits.rank
+		LIMIT ? OFFSET ?
+	`, &sqlitex.ExecOptions{
+		Args: queryArgs,
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			page.Results = append(page.Results, SearchResult{
+				PartWithDiagram: scanPartWithDiagram(stmt),
+				GroupName:       stmt.ColumnText(16),
+				SubgroupName:    nullableString(stmt, 17),
+				Fuzzy:           stmt.ColumnBool(18),
+				Note:            nullableString(stmt, 19),
+			})
+			return nil
+		},
+	})
+	return page, err
+}
+
+// SearchFacets counts the results of a filtered search by group, subgroup,
+// tag, color and attribute, and counts those with replacements or bookmarks.
+func (d *DB) SearchFacets(match *SearchMatch, filter SearchFilter) ([]Facet, error) {
+	if match == nil {
+		return nil, nil
+	}
+
+	where, args := filter.where()
+	queries := []struct {
+		kind FacetKind
+		sql  string
+	}{
+		{FacetGroup, `SELECT g.id, g.name, COUNT(*) AS n` + hitsFrom + ` WHERE ` + where + ` GROUP BY g.id`},
+		{FacetSubgroup, `SELECT s.id, s.name, COUNT(*) AS n` + hitsFrom + ` WHERE ` + where + ` AND s.id IS NOT NULL GROUP BY s.id`},
+		{FacetTag, `SELECT t.id, t.name, COUNT(*) AS n` + hitsFrom + `
+			JOIN tags_to_parts tp ON tp.part_id = p.id
+			JOIN tags t ON t.id = tp.tag_id
+			WHERE ` + where + ` GROUP BY t.id`},
+		{FacetColor, `SELECT p.color, p.color, COUNT(*) AS n` + hitsFrom + ` WHERE ` + where + ` AND COALESCE(p.color, '') != '' GROUP BY p.color`},
+		{FacetAttribute, `SELECT a.kind || '=' || a.value, a.value, COUNT(*) AS n` + hitsFrom + `
+			JOIN part_attributes a ON a.part_id = p.id
+			WHERE ` + where + ` GROUP BY a.kind, a.value`},
+		{FacetReplacement, `SELECT '', 'Has replacement', COUNT(*) AS n` + hitsFrom + ` WHERE ` + where + ` AND COALESCE(p.replacement_part_number, '') != '' HAVING n > 0`},
+		{FacetBookmarked, `SELECT '', 'Bookmarked', COUNT(*) AS n` + hitsFrom + ` WHERE ` + where + ` AND p.id IN (SELECT part_id FROM bookmarks) HAVING n > 0`},
+	}
+
+	var facets []Facet
+	for _, q := range queries {
+		err := sqlitex.Execute(d.conn, hitsCTE+" "+q.sql+" ORDER BY n DESC LIMIT ?", &sqlitex.ExecOptions{
+			Args: append(append(hitsArgs(match.expr), args...), facetLimit),
+			ResultFunc: func(stmt *sqlite.Stmt) error {
+				facets = append(facets, Facet{
+					Kind:  q.kind,
+					Value: stmt.ColumnText(0),
+					Label: stmt.ColumnText(1),
+					Count: stmt.ColumnInt(2),
+				})
+				return nil
+			},
+		})
+		if err != nil {
+			return facets, fmt.Errorf("count %s facets: %w", q.kind, err)
+		}
+	}
+	return facets, nil
+}
@@ -0,0 +1,185 @@
+package db
+
+import (
+	"zombiezen.com/go/sqlite"
+	"zombiezen.com/go/sqlite/sqlitex"
+)
+
+// GetCatalogCounts counts groups, subgroups, diagrams and parts, and the
+// parts that are tagged
+func (d *DB) GetCatalogCounts() (CatalogCounts, error) {
+	var c CatalogCounts
+	err := sqlitex.Execute(d.conn, `
+		SELECT (SELECT COUNT(*) FROM groups),
+			   (SELECT COUNT(*) FROM subgroups),
+			   (SELECT COUNT(*) FROM diagrams),
+			   (SELECT COUNT(*) FROM parts),
+			   (SELECT COUNT(DISTINCT part_id) FROM tags_to_parts)
+	`, &sqlitex.ExecOptions{
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			c = CatalogCounts{
+				Groups:      stmt.ColumnInt(0),
+				Subgroups:   stmt.ColumnInt(1),
+				Diagrams:    stmt.ColumnInt(2),
+				Parts:       stmt.ColumnInt(3),
+				TaggedParts: stmt.ColumnInt(4),
+			}
+			return nil
+		},
+	})
+	return c, err
+}
+
+// GetSubgroupCounts counts the parts and diagrams in every subgroup, in the
+// order groups and subgroups are listed. Empty subgroups are included, and
+// a group without subgroups appears once with no subgroup.
+func (d *DB) GetSubgroupCounts() ([]SubgroupCount, error) {
+	var counts []SubgroupCount
+	err := sqlitex.Execute(d.conn, `
+		SELECT g.id, g.name, s.id, s.name,
+			   (SELECT COUNT(*) FROM parts p WHERE p.subgroup_id = s.id),
+			   (SELECT COUNT(*) FROM diagrams dg WHERE dg.subgroup_id = s.id)
+		FROM groups g
+		LEFT JOIN subgroups s ON s.group_id = g.id
+		ORDER BY g.name, s.name
+	`, &sqlitex.ExecOptions{
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			counts = append(counts, SubgroupCount{
+				GroupID:      stmt.ColumnText(0),
+				GroupName:    stmt.ColumnText(1),
+				SubgroupID:   stmt.ColumnText(2),
+				SubgroupName: stmt.ColumnText(3),
+				Parts:        stmt.ColumnInt(4),
+				Diagrams:     stmt.ColumnInt(5),
+			})
+			return nil
+		},
+	})
+	return counts, err
+}
+
+// GetDiagramImages lists every diagram with the image file recorded for it,
+// if any
+func (d *DB) GetDiagramImages() ([]DiagramImage, error) {
+	var images []DiagramImage
+	err := sqlitex.Execute(d.conn, `
+		SELECT id, subgroup_id, COALESCE(image_path, '')
+		FROM diagrams
+		ORDER BY id
+	`, &sqlitex.ExecOptions{
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			images = append(images, DiagramImage{
+				DiagramID:  stmt.ColumnText(0),
+				SubgroupID: nullableString(stmt, 1),
+				Path:       stmt.ColumnText(2),
+			})
+			return nil
+		},
+	})
+	return images, err
+}
+
+// partRefQuery selects parts as PartRefs; the caller adds the conditions
+const partRefQuery = `
+	SELECT p.id, p.part_number, p.description, g.name, s.name, p.replacement_part_number
+	FROM parts p
+	JOIN groups g ON p.group_id = g.id
+	LEFT JOIN subgroups s ON p.subgroup_id = s.id
+	LEFT JOIN diagrams dg ON p.diagram_id = dg.id
+`
+
+func (d *DB) getPartRefs(where string) ([]PartRef, error) {
+	var parts []PartRef
+	err := sqlitex.Execute(d.conn, partRefQuery+"WHERE "+where+" ORDER BY g.name, s.name, p.part_number", &sqlitex.ExecOptions{
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			parts = append(parts, PartRef{
+				ID:           stmt.ColumnInt(0),
+				PartNumber:   stmt.ColumnText(1),
+				Description:  nullableString(stmt, 2),
+				GroupName:    stmt.ColumnText(3),
+				SubgroupName: nullableString(stmt, 4),
+				Replacement:  nullableString(stmt, 5),
+			})
+			return nil
+		},
+	})
+	return parts, err
+}
+
+// GetPartsWithoutDiagrams returns the parts whose diagram isn't in the
+// catalog
+func (d *DB) GetPartsWithoutDiagrams() ([]PartRef, error) {
+	return d.getPartRefs("dg.id IS NULL")
+}
+
+// GetReplacedParts returns the parts that have been replaced by another
+// part number
+func (d *DB) GetReplacedParts() ([]PartRef, error) {
+	return d.getPartRefs("p.replacement_part_number IS NOT NULL AND p.replacement_part_number != ''")
+}
+
+// GetTagCounts returns every tag with the number of parts that have it, by
+// category then name
+func (d *DB) GetTagCounts() ([]TagCount, error) {
+	var tags []TagCount
+	err := sqlitex.Execute(d.conn, `
+		SELECT t.id, t.name, t.category, COUNT(tp.part_id)
+		FROM tags t
+		LEFT JOIN tags_to_parts tp ON tp.tag_id = t.id
+		GROUP BY t.id
+		ORDER BY t.category, t.name
+	`, &sqlitex.ExecOptions{
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			tags = append(tags, TagCount{
+				Tag: Tag{
+					ID:       stmt.ColumnText(0),
+					Name:     stmt.ColumnText(1),
+					Category: stmt.ColumnText(2),
+				},
+				Parts: stmt.ColumnInt(3),
+			})
+			return nil
+		},
+	})
+	return tags, err
+}
+
+// GetScrapeProgress sums up the scraper's progress table, listing failed
+// pages by URL. It returns nil if the database has no progress table, as
+// when it wasn't built by the scraper.
+func (d *DB) GetScrapeProgress() (*ScrapeProgress, error) {
+	exists, err := tableExists(d.conn, "scrape_progress")
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	p := &ScrapeProgress{}
+	err = sqlitex.Execute(d.conn, "SELECT status, COUNT(*) FROM scrape_progress GROUP BY status", &sqlitex.ExecOptions{
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			switch stmt.ColumnText(0) {
+			case "completed":
+				p.Completed = stmt.ColumnInt(1)
+			case "failed":
+				p.Failed = stmt.ColumnInt(1)
+			case "pending":
+				p.Pending = stmt.ColumnInt(1)
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = sqlitex.Execute(d.conn, "SELECT url, error, scraped_at FROM scrape_progress WHERE status = 'failed' ORDER BY url", &sqlitex.ExecOptions{
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			p.Failures = append(p.Failures, ScrapeFailure{
+				URL:       stmt.ColumnText(0),
+				Error:     nullableString(stmt, 1),
+				ScrapedAt: nullableString(stmt, 2),
+			})
+			return nil
+		},
+	})
+	return p, err
+}
@@ -5,6 +5,12 @@ type Group struct {
 	Name string
 }
 
+type Tag struct {
+	ID       string
+	Name     string
+	Category string
+}
+
 type Subgroup struct {
 	ID      string
 	Name    string
@@ -48,6 +54,46 @@ type SearchResult struct {
 	PartWithDiagram
 	GroupName    string
 	SubgroupName *string
+	Fuzzy        bool    // Matched a spelling-corrected query rather than the original
+	Note         *string // Contents of a note the query matched, if any
+}
+
+// SearchPage is one page of filtered search results.
+type SearchPage struct {
+	Results    []SearchResult
+	Total      int    // Matches across all pages
+	Suggestion string // Spelling-corrected query, when fuzzy matching kicked in
+}
+
+// FacetKind identifies what a search facet filters on.
+type FacetKind string
+
+const (
+	FacetGroup       FacetKind = "group"
+	FacetSubgroup    FacetKind = "subgroup"
+	FacetTag         FacetKind = "tag"
+	FacetColor       FacetKind = "color"
+	FacetAttribute   FacetKind = "attribute"
+	FacetReplacement FacetKind = "replacement"
+	FacetBookmarked  FacetKind = "bookmarked"
+)
+
+// Facet is one value search results can be narrowed to, with the number of
+// current results that have it.
+type Facet struct {
+	Kind  FacetKind
+	Value string // ID or value matched by the filter
+	Label string
+	Count int
+}
+
+// SavedSearch is a named search query with its filters.
+type SavedSearch struct {
+	ID        int
+	Name      string
+	Query     string
+	Filter    SearchFilter
+	CreatedAt string
 }
 
 type BookmarkResult struct {
@@ -61,10 +107,44 @@ type BookmarkResult struct {
 	CreatedAt    string
 }
 
+// OrderItem is a part on the order list.
+type OrderItem struct {
+	ID           int
+	PartID       int
+	PartNumber   string
+	PNC          *string
+	Description  *string
+	GroupName    string
+	SubgroupName *string
+	CreatedAt    string
+}
+
+// Note is one entry in a part's notes journal.
+type Note struct {
+	ID        int
+	PartID    int
+	Content   string
+	Author    string
+	Tags      []string
+	CreatedAt string
+	UpdatedAt string
+}
+
+// Attachment is a file kept with a part number, such as a photo of the part.
+type Attachment struct {
+	ID         int
+	PartNumber string
+	Name       string
+	Path       string // Relative to the data directory
+	Size       int64
+	CreatedAt  string
+}
+
 type NoteResult struct {
 	ID           int
 	PartID       int
-	Content      string
+	Content      string // Latest entry for the part
+	Count        int    // Entries for the part
 	PartNumber   string
 	PNC          *string
 	Description  *string
@@ -79,3 +159,61 @@ type SubgroupWithGroup struct {
 	GroupID      string
 	GroupName    string
 }
+
+// CatalogCounts is how much the catalog holds.
+type CatalogCounts struct {
+	Groups      int
+	Subgroups   int
+	Diagrams    int
+	Parts       int
+	TaggedParts int // Parts with at least one tag
+}
+
+// SubgroupCount is how many parts and diagrams a subgroup has.
+type SubgroupCount struct {
+	GroupID      string
+	GroupName    string
+	SubgroupID   string // "" for a group without subgroups
+	SubgroupName string
+	Parts        int
+	Diagrams     int
+}
+
+// DiagramImage is a diagram and the image file recorded for it.
+type DiagramImage struct {
+	DiagramID  string
+	SubgroupID *string
+	Path       string // Relative to the data directory; "" if none is recorded
+}
+
+// PartRef names a part and where it sits in the catalog.
+type PartRef struct {
+	ID           int
+	PartNumber   string
+	Description  *string
+	GroupName    string
+	SubgroupName *string
+	Replacement  *string // Part number it was replaced by, if any
+}
+
+// TagCount is a tag and how many parts have it.
+type TagCount struct {
+	Tag
+	Parts int
+}
+
+// ScrapeProgress sums up the scraper's progress table: how many pages were
+// scraped, failed or are still to do, and why the failed ones failed.
+type ScrapeProgress struct {
+	Completed int
+	Failed    int
+	Pending   int
+	Failures  []ScrapeFailure
+}
+
+// ScrapeFailure is a page the scraper couldn't read.
+type ScrapeFailure struct {
+	URL       string
+	Error     *string
+	ScrapedAt *string
+}
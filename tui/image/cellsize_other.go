@@ -0,0 +1,8 @@
+//go:build !unix
+
+package image
+
+// terminalCellSize can't ask the terminal on this platform
+func terminalCellSize() (width, height int) {
+	return 0, 0
+}
@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"image/png"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"sync/atomic"
 
 	"github.com/disintegration/imaging"
@@ -13,6 +16,30 @@ import (
 
 var imageIDCounter uint32
 
+// Assumed size of a terminal cell in pixels, used to scale diagrams
+const (
+	estCellWidth  = 10
+	estCellHeight = 20
+)
+
+var (
+	cellOnce   sync.Once
+	cellWidth  = estCellWidth
+	cellHeight = estCellHeight
+)
+
+// CellSize returns the size of a terminal cell in pixels, as the terminal
+// reports it, or the 10x20 estimate if it doesn't. Image scaling keeps to
+// the estimate; this is for laying out images by the cells' real shape.
+func CellSize() (width, height int) {
+	cellOnce.Do(func() {
+		if w, h := terminalCellSize(); w > 0 && h > 0 {
+			cellWidth, cellHeight = w, h
+		}
+	})
+	return cellWidth, cellHeight
+}
+
 // KittyImage represents an image prepared for Kitty protocol rendering
 type KittyImage struct {
 	data   string // base64 encoded PNG
@@ -21,6 +48,15 @@ type KittyImage struct {
 	id     uint32
 }
 
+// IsImage reports whether a file has an image format LoadAndScale can read
+func IsImage(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png", ".jpg", ".jpeg", ".gif", ".bmp", ".tif", ".tiff":
+		return true
+	}
+	return false
+}
+
 // LoadAndScale loads an image, scales it to fit within maxWidth x maxHeight cells,
 // and prepares it for Kitty protocol rendering.
 // Assumes ~10 pixels per cell width, ~20 pixels per cell height.
@@ -30,15 +66,15 @@ func LoadAndScale(path string, maxWidthCells, maxHeightCells int) (*KittyImage,
 		return nil, fmt.Errorf("file not found: %s", path)
 	}
 
-	// Load image
-	img, err := imaging.Open(path)
+	// Load image, turning photos upright per their EXIF orientation
+	img, err := imaging.Open(path, imaging.AutoOrientation(true))
 	if err != nil {
 		return nil, fmt.Errorf("open image: %w", err)
 	}
 
 	// Convert cells to pixels (approximate)
-	maxWidthPx := maxWidthCells * 10
-	maxHeightPx := maxHeightCells * 20
+	maxWidthPx := maxWidthCells * estCellWidth
+	maxHeightPx := maxHeightCells * estCellHeight
 
 	// Scale to fit
 	bounds := img.Bounds()
@@ -82,11 +118,36 @@ func LoadAndScale(path string, maxWidthCells, maxHeightCells int) (*KittyImage,
 // The image is transmitted and displayed in one command.
 // Note: Caller is responsible for cursor positioning if needed.
 func (img *KittyImage) Render() string {
+	return img.render("T", "")
+}
+
+// RenderSize returns the escape sequence to display the image scaled
+// to cover exactly cols x rows terminal cells.
+func (img *KittyImage) RenderSize(cols, rows int) string {
+	// c=<cols>, r=<rows> - number of cells to display the image over
+	return img.render("T", fmt.Sprintf(",c=%d,r=%d", cols, rows))
+}
+
+// Transmit returns the escape sequence to send the image to the terminal
+// without displaying it, so it can be placed later with Place.
+func (img *KittyImage) Transmit() string {
+	return img.render("t", "")
+}
+
+// Place returns the escape sequence to display an image already sent with
+// Transmit over cols x rows cells. Placing it again moves the placement.
+func (img *KittyImage) Place(cols, rows int) string {
+	// a=p - place a transmitted image
+	// p=1 - placement ID, so each image has one placement
+	return fmt.Sprintf("\x1b_Ga=p,i=%d,p=1,c=%d,r=%d,q=2\x1b\\", img.id, cols, rows)
+}
+
+func (img *KittyImage) render(action, placement string) string {
 	// Kitty graphics protocol:
 	// \x1b_G<key>=<value>,...;<payload>\x1b\\
 	//
 	// Keys:
-	// a=T - transmit and display
+	// a=T - transmit and display (a=t transmits only)
 	// f=100 - PNG format
 	// t=d - direct transmission
 	// i=<id> - image ID
@@ -115,8 +176,8 @@ func (img *KittyImage) Render() string {
 
 		result.WriteString("\x1b_G")
 		if first {
-			result.WriteString(fmt.Sprintf("a=T,f=100,t=d,i=%d,s=%d,v=%d,q=2,m=%d;",
-				img.id, img.width, img.height, more))
+			result.WriteString(fmt.Sprintf("a=%s,f=100,t=d,i=%d,s=%d,v=%d%s,q=2,m=%d;",
+				action, img.id, img.width, img.height, placement, more))
 			first = false
 		} else {
 			result.WriteString(fmt.Sprintf("m=%d;", more))
@@ -136,6 +197,13 @@ func Clear(id uint32) string {
 	return fmt.Sprintf("\x1b_Ga=d,d=I,i=%d,q=2\x1b\\", id)
 }
 
+// Hide returns the escape sequence to remove an image's placements while
+// keeping its data, so it can be placed again without sending it.
+func Hide(id uint32) string {
+	// d=i - delete placements by ID, keeping the image data
+	return fmt.Sprintf("\x1b_Ga=d,d=i,i=%d,q=2\x1b\\", id)
+}
+
 // ClearAll returns the escape sequence to delete all images.
 func ClearAll() string {
 	return "\x1b_Ga=d,d=A,q=2\x1b\\"
@@ -155,3 +223,39 @@ func (img *KittyImage) CellHeight() int {
 func (img *KittyImage) CellWidth() int {
 	return (img.width + 9) / 10 // Round up
 }
+
+// Fit returns the cell size the image occupies when scaled to fit within
+// maxCols x maxRows, preserving its aspect ratio. Images are never scaled up.
+func (img *KittyImage) Fit(maxCols, maxRows int) (cols, rows int) {
+	return fitCells(img.CellWidth(), img.CellHeight(), maxCols, maxRows)
+}
+
+// FitCells is Fit for cells of the given size in pixels, such as those
+// from CellSize
+func (img *KittyImage) FitCells(maxCols, maxRows, cellWidth, cellHeight int) (cols, rows int) {
+	cols = (img.width + cellWidth - 1) / cellWidth
+	rows = (img.height + cellHeight - 1) / cellHeight
+	return fitCells(cols, rows, maxCols, maxRows)
+}
+
+// fitCells shrinks cols x rows to fit within maxCols x maxRows
+func fitCells(cols, rows, maxCols, maxRows int) (int, int) {
+	if maxCols <= 0 || maxRows <= 0 {
+		return 0, 0
+	}
+	if cols > maxCols {
+		rows = rows * maxCols / cols
+		cols = maxCols
+	}
+	if rows > maxRows {
+		cols = cols * maxRows / rows
+		rows = maxRows
+	}
+	if cols < 1 {
+		cols = 1
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	return cols, rows
+}
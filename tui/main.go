@@ -5,9 +5,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
+	"delica-tui/bom"
+	"delica-tui/config"
 	"delica-tui/db"
 	"delica-tui/model"
+	"delica-tui/stats"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/joho/godotenv"
@@ -15,8 +20,18 @@ import (
 
 func main() {
 	dataPath := flag.String("data", "./data", "Path to data directory (contains delica.db and images/)")
+	partID := flag.Int("part", 0, "Open a part by ID")
+	partNumber := flag.String("part-number", "", "Open a part by part number")
+	subgroupID := flag.String("subgroup", "", "Open a subgroup by ID (e.g. engine/cylinder-head)")
+	query := flag.String("search", "", "Open search with a query")
 	flag.Parse()
 
+	if countSet(*partID != 0, *partNumber != "", *subgroupID != "", *query != "") > 1 {
+		fmt.Fprintln(os.Stderr, "only one of -part, -part-number, -subgroup and -search can be given")
+		flag.Usage()
+		os.Exit(2)
+	}
+
 	// Resolve to absolute path
 	absDataPath, err := filepath.Abs(*dataPath)
 	if err != nil {
@@ -36,11 +51,179 @@ func main() {
 	}
 	defer database.Close()
 
-	m := model.New(database, absDataPath)
-	p := tea.NewProgram(m, tea.WithAltScreen())
+	// "export" writes a bill of materials instead of starting the TUI
+	if flag.Arg(0) == "export" {
+		if err := runExport(database, absDataPath, flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			database.Close()
+			os.Exit(1)
+		}
+		return
+	}
+
+	// "stats" reports catalog coverage and scrape progress
+	if flag.Arg(0) == "stats" {
+		if err := runStats(database, absDataPath, flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			database.Close()
+			os.Exit(1)
+		}
+		return
+	}
+
+	cfg, err := config.Load(filepath.Join(absDataPath, "config.json"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	m := model.New(database, absDataPath, cfg)
+
+	// Start on the screen given by a flag, if any
+	start, err := startScreen(database, *partID, *partNumber, *subgroupID, *query)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		database.Close()
+		os.Exit(1)
+	}
+	if start != nil {
+		m.StartAt(*start)
+	}
+
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
 
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// startScreen resolves the startup flags to a screen, or nil for Home
+func startScreen(database *db.DB, partID int, partNumber, subgroupID, query string) (*model.Screen, error) {
+	switch {
+	case partID != 0:
+		part, err := database.GetPart(partID)
+		if err != nil {
+			return nil, fmt.Errorf("look up part: %w", err)
+		}
+		if part == nil {
+			return nil, fmt.Errorf("no part with ID %d", partID)
+		}
+		s := model.PartDetailScreen(partID, false)
+		return &s, nil
+
+	case partNumber != "":
+		id, err := database.FindPartID(partNumber)
+		if err != nil {
+			return nil, fmt.Errorf("look up part: %w", err)
+		}
+		if id == 0 {
+			return nil, fmt.Errorf("no part numbered %s", partNumber)
+		}
+		s := model.PartDetailScreen(id, false)
+		return &s, nil
+
+	case subgroupID != "":
+		subgroup, err := database.GetSubgroup(subgroupID)
+		if err != nil {
+			return nil, fmt.Errorf("look up subgroup: %w", err)
+		}
+		if subgroup == nil {
+			return nil, fmt.Errorf("no subgroup %s", subgroupID)
+		}
+		s := model.SubgroupScreen(subgroupID)
+		return &s, nil
+
+	case query != "":
+		s := model.SearchScreen(query)
+		return &s, nil
+	}
+	return nil, nil
+}
+
+// countSet returns how many of a set of mutually exclusive flags were given
+func countSet(set ...bool) int {
+	n := 0
+	for _, s := range set {
+		if s {
+			n++
+		}
+	}
+	return n
+}
+
+// runExport handles the export subcommand: write the BOM for one subgroup,
+// group or tag to a file, or to stdout
+func runExport(database *db.DB, dataPath string, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	subgroupID := fs.String("subgroup", "", "Export a subgroup by ID (e.g. engine/cylinder-head)")
+	groupID := fs.String("group", "", "Export every subgroup in a group by ID")
+	tagID := fs.String("tag", "", "Export every part with a tag by ID")
+	format := fs.String("format", "csv", "Output format: "+strings.Join(bom.Formats, ", "))
+	output := fs.String("o", "", "Write to a file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if !slices.Contains(bom.Formats, *format) {
+		return fmt.Errorf("unknown format %s (want %s)", *format, strings.Join(bom.Formats, ", "))
+	}
+	if countSet(*subgroupID != "", *groupID != "", *tagID != "") > 1 {
+		fs.Usage()
+		return fmt.Errorf("export takes only one of -subgroup, -group and -tag")
+	}
+
+	var b *bom.BOM
+	var err error
+	switch {
+	case *subgroupID != "":
+		b, err = bom.ForSubgroup(database, dataPath, *subgroupID)
+	case *groupID != "":
+		b, err = bom.ForGroup(database, dataPath, *groupID)
+	case *tagID != "":
+		b, err = bom.ForTag(database, dataPath, *tagID)
+	default:
+		return fmt.Errorf("export needs -subgroup, -group or -tag")
+	}
+	if err != nil {
+		return fmt.Errorf("build export: %w", err)
+	}
+
+	if *output == "" {
+		return bom.Write(os.Stdout, b, *format, "")
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(*output))
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", *output, err)
+	}
+	if err := bom.Write(f, b, *format, dir); err != nil {
+		f.Close()
+		return fmt.Errorf("write %s: %w", *output, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("write %s: %w", *output, err)
+	}
+	return nil
+}
+
+// runStats handles the stats subcommand: report what the catalog holds and
+// lacks, and how far the scrape got
+func runStats(database *db.DB, dataPath string, args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	format := fs.String("format", "text", "Output format: "+strings.Join(stats.Formats, ", "))
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if !slices.Contains(stats.Formats, *format) {
+		return fmt.Errorf("unknown format %s (want %s)", *format, strings.Join(stats.Formats, ", "))
+	}
+
+	r, err := stats.Build(database, dataPath)
+	if err != nil {
+		return fmt.Errorf("gather stats: %w", err)
+	}
+	return stats.Write(os.Stdout, r, *format)
+}
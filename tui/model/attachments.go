@@ -0,0 +1,88 @@
+package model
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// attachmentsDir is where attached files are kept, under the data directory
+const attachmentsDir = "attachments"
+
+// storeAttachment copies a file into the part number's attachments
+// directory, numbering the name if it is already taken. It returns the
+// stored name and its path relative to the data directory.
+func storeAttachment(dataPath, partNumber, src string) (name, rel string, size int64, err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return "", "", 0, err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return "", "", 0, err
+	}
+	if info.IsDir() {
+		return "", "", 0, fmt.Errorf("%s is a directory", src)
+	}
+
+	dir := filepath.Join(attachmentsDir, strings.ReplaceAll(partNumber, string(filepath.Separator), "_"))
+	if err := os.MkdirAll(filepath.Join(dataPath, dir), 0755); err != nil {
+		return "", "", 0, err
+	}
+
+	// Pick a free name: photo.jpg, photo-2.jpg, ...
+	base := filepath.Base(src)
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	name = base
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filepath.Join(dataPath, dir, name)); os.IsNotExist(err) {
+			break
+		}
+		name = fmt.Sprintf("%s-%d%s", stem, i, ext)
+	}
+	rel = filepath.Join(dir, name)
+
+	out, err := os.OpenFile(filepath.Join(dataPath, rel), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return "", "", 0, err
+	}
+	size, err = io.Copy(out, in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(filepath.Join(dataPath, rel))
+		return "", "", 0, err
+	}
+	return name, rel, size, nil
+}
+
+// cleanInputPath tidies a path typed or dropped into the terminal:
+// surrounding quotes and escaped spaces are removed and ~ is expanded
+func cleanInputPath(path string) string {
+	path = strings.TrimSpace(path)
+	path = strings.Trim(path, `"'`)
+	path = strings.ReplaceAll(path, `\ `, " ")
+	if rest, ok := strings.CutPrefix(path, "~"); ok && (rest == "" || rest[0] == '/') {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = home + rest
+		}
+	}
+	return path
+}
+
+// formatSize shows a file size in B, KB or MB
+func formatSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.0f KB", float64(size)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", size)
+}
@@ -0,0 +1,170 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"delica-tui/db"
+	"delica-tui/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// batch picks several parts in a list menu, whose item IDs are part IDs,
+// and runs an action on them all: ctrl+x followed by the action's key.
+type batch struct {
+	choosing bool   // ctrl+x was pressed; the next key picks the action
+	status   string // Result of the last action
+	changed  bool   // The last action changed bookmarks or the order list
+}
+
+// active reports whether esc should clear the selection rather than go back
+func (b *batch) active(menu *ui.Menu) bool {
+	return b.choosing || menu.MarkedCount() > 0
+}
+
+// update applies selection and action keys and reports whether the key was
+// used. On screens that are typing, space types rather than picks and
+// ctrl+a moves to the start of the input.
+func (b *batch) update(database *db.DB, dataPath string, menu *ui.Menu, msg tea.KeyMsg, typing bool) (bool, tea.Cmd) {
+	b.status = ""
+	b.changed = false
+
+	if b.choosing {
+		b.choosing = false
+		if ui.IsBack(msg) {
+			return true, nil
+		}
+		return true, b.run(database, dataPath, menu, msg)
+	}
+
+	switch {
+	case ui.IsMark(msg), ui.IsToggle(msg) && !typing:
+		menu.ToggleMark()
+	case ui.IsMarkUp(msg):
+		menu.Mark()
+		menu.Up()
+		menu.Mark()
+	case ui.IsMarkDown(msg):
+		menu.Mark()
+		menu.Down()
+		menu.Mark()
+	case ui.IsMarkAll(msg) && !typing:
+		menu.MarkAll()
+	case ui.IsBatchActions(msg) && menu.MarkedCount() > 0:
+		b.choosing = true
+	case ui.IsBack(msg) && menu.MarkedCount() > 0:
+		menu.ClearMarks()
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// run applies the action chosen by key to the picked parts, then clears
+// the selection. Other keys cancel.
+func (b *batch) run(database *db.DB, dataPath string, menu *ui.Menu, msg tea.KeyMsg) tea.Cmd {
+	var ids []int
+	for _, item := range menu.Marked() {
+		var partID int
+		fmt.Sscanf(item.ID, "%d", &partID)
+		ids = append(ids, partID)
+	}
+
+	var cmd tea.Cmd
+	switch msg.String() {
+	case "b":
+		b.apply(ids, "Bookmarked %s", database.IsBookmarked, false, database.AddBookmark)
+	case "u":
+		b.apply(ids, "Unbookmarked %s", database.IsBookmarked, true, database.RemoveBookmark)
+	case "o":
+		b.apply(ids, "Added %s to the order list", database.IsInOrder, false, database.AddToOrder)
+	case "y":
+		// One line per part number, without repeats
+		var numbers []string
+		seen := make(map[string]bool)
+		for _, p := range loadParts(database, ids) {
+			if !seen[p.PartNumber] {
+				seen[p.PartNumber] = true
+				numbers = append(numbers, p.PartNumber)
+			}
+		}
+		text, confirm := strings.Join(numbers, "\n"), fmt.Sprintf("Copied %d part numbers", len(numbers))
+		cmd = func() tea.Msg {
+			return copyMsg{text: text, confirm: confirm}
+		}
+	case "e":
+		path, err := exportParts(database, dataPath, "Selection", loadParts(database, ids), "csv")
+		if err != nil {
+			b.status = ui.ErrorStyle.Render("Export failed: " + err.Error())
+			return nil
+		}
+		b.status = "Exported to " + path
+	default:
+		return nil
+	}
+
+	menu.ClearMarks()
+	return cmd
+}
+
+// apply runs action on the parts whose state, as reported by is, equals from,
+// and reports how many changed and how many failed
+func (b *batch) apply(ids []int, done string, is func(int) (bool, error), from bool, action func(int) error) {
+	changed := 0
+	var errs []error
+	for _, id := range ids {
+		state, err := is(id)
+		if err == nil && state != from {
+			continue
+		}
+		if err == nil {
+			err = action(id)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		changed++
+	}
+
+	b.status = fmt.Sprintf(done, countParts(changed))
+	if len(errs) > 0 {
+		b.status = ui.ErrorStyle.Render(fmt.Sprintf("%s, %d failed: %v", b.status, len(errs), errs[0]))
+	}
+	b.changed = changed > 0
+}
+
+// loadParts looks up parts by ID, skipping any that no longer exist
+func loadParts(database *db.DB, ids []int) []db.PartWithDiagram {
+	var parts []db.PartWithDiagram
+	for _, id := range ids {
+		if p, _ := database.GetPart(id); p != nil {
+			parts = append(parts, *p)
+		}
+	}
+	return parts
+}
+
+func countParts(n int) string {
+	if n == 1 {
+		return "1 part"
+	}
+	return fmt.Sprintf("%d parts", n)
+}
+
+// footer returns the help line to show in place of the list's own while
+// parts are picked, or "" when there is no selection
+func (b *batch) footer(menu *ui.Menu, typing bool) string {
+	switch {
+	case b.status != "":
+		return b.status
+	case b.choosing:
+		return "b bookmark   u unbookmark   o order   y copy   e export   esc cancel"
+	case menu.MarkedCount() > 0 && typing:
+		return fmt.Sprintf("%d picked   ctrl+x actions   esc clear", menu.MarkedCount())
+	case menu.MarkedCount() > 0:
+		return fmt.Sprintf("%d picked   ctrl+x actions   ctrl+a all   esc clear", menu.MarkedCount())
+	}
+	return ""
+}
@@ -13,11 +13,13 @@ import (
 
 type BookmarksModel struct {
 	db        *db.DB
+	dataPath  string
 	bookmarks []db.BookmarkResult
 	menu      *ui.Menu
+	batch     batch
 }
 
-func NewBookmarksModel(database *db.DB) *BookmarksModel {
+func NewBookmarksModel(database *db.DB, dataPath string) *BookmarksModel {
 	bookmarks, _ := database.GetBookmarks()
 
 	var items []ui.MenuItem
@@ -47,6 +49,7 @@ func NewBookmarksModel(database *db.DB) *BookmarksModel {
 
 	return &BookmarksModel{
 		db:        database,
+		dataPath:  dataPath,
 		bookmarks: bookmarks,
 		menu:      ui.NewMenu(items),
 	}
@@ -55,6 +58,16 @@ func NewBookmarksModel(database *db.DB) *BookmarksModel {
 func (m *BookmarksModel) Update(msg tea.Msg) (*BookmarksModel, tea.Cmd, *Screen) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if handled, cmd := m.batch.update(m.db, m.dataPath, m.menu, msg, false); handled {
+			if m.batch.changed {
+				// Reload so unbookmarked parts leave the list
+				cursor, status := m.menu.Cursor, m.batch.status
+				*m = *NewBookmarksModel(m.db, m.dataPath)
+				m.menu.SetCursor(cursor)
+				m.batch.status = status
+			}
+			return m, cmd, nil
+		}
 		if ui.IsUp(msg) {
 			m.menu.Up()
 		}
@@ -62,18 +75,29 @@ func (m *BookmarksModel) Update(msg tea.Msg) (*BookmarksModel, tea.Cmd, *Screen)
 			m.menu.Down()
 		}
 		if ui.IsEnter(msg) {
-			if item := m.menu.Selected(); item != nil {
-				var partID int
-				fmt.Sscanf(item.ID, "%d", &partID)
-				s := PartDetailScreen(partID, false)
-				return m, nil, &s
-			}
+			return m.openSelected()
+		}
+
+	case tea.MouseMsg:
+		if m.menu.Mouse(msg) {
+			return m.openSelected()
 		}
 	}
 	return m, nil, nil
 }
 
-func (m *BookmarksModel) View(width, height int) string {
+// openSelected navigates to the item under the cursor
+func (m *BookmarksModel) openSelected() (*BookmarksModel, tea.Cmd, *Screen) {
+	if item := m.menu.Selected(); item != nil {
+		var partID int
+		fmt.Sscanf(item.ID, "%d", &partID)
+		s := PartDetailScreen(partID, false)
+		return m, nil, &s
+	}
+	return m, nil, nil
+}
+
+func (m *BookmarksModel) View(width, height int, layout ui.SplitLayout) string {
 	if width == 0 {
 		width = 80
 	}
@@ -95,10 +119,11 @@ func (m *BookmarksModel) View(width, height int) string {
 		splitHeight = 10
 	}
 
-	leftContent := m.renderLeftPane(splitHeight)
-	rightContent := m.renderRightPane(splitHeight)
+	leftPane, rightPane := layout.Panes(width-2, splitHeight)
+	leftContent := m.renderLeftPane(leftPane.Height)
+	rightContent := m.renderRightPane(rightPane)
 
-	split := ui.RenderSplitPane(leftContent, rightContent, width-2, splitHeight)
+	split := ui.RenderSplitPane(leftContent, rightContent, width-2, splitHeight, layout)
 
 	return header + "\n" + split
 }
@@ -122,7 +147,7 @@ func (m *BookmarksModel) renderLeftPane(height int) string {
 	return strings.Join(lines, "\n")
 }
 
-func (m *BookmarksModel) renderRightPane(height int) string {
+func (m *BookmarksModel) renderRightPane(pane ui.Rect) string {
 	var b strings.Builder
 
 	// Header
@@ -131,7 +156,7 @@ func (m *BookmarksModel) renderRightPane(height int) string {
 	b.WriteString(ui.DimStyle.Render("─────────────────────────────────"))
 
 	// Adjust menu visible items based on available height (max 15)
-	menuHeight := height - 5
+	menuHeight := pane.Height - 5
 	if menuHeight < 5 {
 		menuHeight = 5
 	}
@@ -155,11 +180,16 @@ func (m *BookmarksModel) renderRightPane(height int) string {
 		b.WriteString("\n")
 		b.WriteString(ui.DimStyle.Render("press 'b' to bookmark it"))
 	} else {
+		m.menu.SetOrigin(pane.X, splitTop+pane.Y+strings.Count(b.String(), "\n"), pane.Width)
 		b.WriteString(m.menu.View())
 	}
 
+	help := "↑↓ navigate   enter select   f filter   space pick"
+	if footer := m.batch.footer(m.menu, false); footer != "" {
+		help = footer
+	}
 	b.WriteString("\n\n")
-	b.WriteString(ui.DimStyle.Render("↑↓ navigate   enter select"))
+	b.WriteString(ui.DimStyle.Render(help))
 
 	return b.String()
 }
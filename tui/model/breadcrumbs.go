@@ -0,0 +1,168 @@
+package model
+
+import (
+	"strings"
+
+	"delica-tui/db"
+	"delica-tui/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// crumbWidth caps the length of each breadcrumb label
+const crumbWidth = 28
+
+// crumb is one level of the breadcrumb bar drawn on the top row
+type crumb struct {
+	label  string
+	screen Screen
+
+	// Columns covered by the last render, for clicks
+	x     int
+	width int
+}
+
+// updateCrumbs rebuilds the breadcrumb trail for the current screen, from
+// Home down through its group and subgroup
+func (m *Model) updateCrumbs() {
+	crumbs := []crumb{{label: "Home", screen: HomeScreen()}}
+	add := func(label string, s Screen) {
+		crumbs = append(crumbs, crumb{label: truncate(label, crumbWidth), screen: s})
+	}
+	addGroup := func(g *db.Group) {
+		if g != nil {
+			add(g.Name, GroupScreen(g.ID))
+		}
+	}
+	addSubgroup := func(s *db.Subgroup) {
+		if s != nil {
+			add(s.Name, SubgroupScreen(s.ID))
+		}
+	}
+
+	switch m.screen.Type {
+	case ScreenGroup:
+		addGroup(m.group.group)
+	case ScreenSheet:
+		addGroup(m.sheet.group)
+		add("Contact sheet", m.screen)
+	case ScreenSubgroup:
+		addGroup(m.subgroup.group)
+		addSubgroup(m.subgroup.subgroup)
+	case ScreenPartDetail:
+		addGroup(m.partDetail.group)
+		addSubgroup(m.partDetail.subgroup)
+		if m.partDetail.part != nil {
+			add(m.partDetail.part.PartNumber, m.screen)
+		}
+	case ScreenSearch:
+		add("Search", m.screen)
+	case ScreenBookmarks:
+		add("Bookmarks", m.screen)
+	case ScreenNotes:
+		add("Notes", m.screen)
+	case ScreenJumps:
+		add("Recent", m.screen)
+	case ScreenOrder:
+		add("Order List", m.screen)
+	case ScreenStats:
+		add("Catalog Stats", m.screen)
+	}
+
+	m.crumbs = crumbs
+	m.crumbCursor = -1
+}
+
+// updateCrumbKeys moves through the focused breadcrumb bar; enter jumps to
+// the selected level and esc returns focus to the screen
+func (m *Model) updateCrumbKeys(msg tea.KeyMsg) (*Model, tea.Cmd) {
+	switch {
+	case ui.IsLeft(msg):
+		m.crumbCursor = max(m.crumbCursor-1, 0)
+	case ui.IsRight(msg):
+		m.crumbCursor = min(m.crumbCursor+1, len(m.crumbs)-1)
+	case ui.IsEnter(msg):
+		return m.openCrumb(m.crumbCursor)
+	case ui.IsBack(msg), ui.IsBreadcrumbs(msg):
+		m.crumbCursor = -1
+	}
+	return m, nil
+}
+
+// openCrumb goes to breadcrumb i; the last one is the current screen. If
+// the crumb's screen is in the history, it goes back to it as if esc had
+// been pressed until it was reached, and otherwise opens it afresh.
+func (m *Model) openCrumb(i int) (*Model, tea.Cmd) {
+	m.crumbCursor = -1
+	if i < 0 || i >= len(m.crumbs)-1 {
+		return m, nil
+	}
+
+	key := m.crumbs[i].screen.key()
+	for j := len(m.history) - 1; j >= 0; j-- {
+		if m.history[j].key() != key {
+			continue
+		}
+		m.leave()
+		m.forward = append(m.forward, m.screen)
+		for k := len(m.history) - 1; k > j; k-- {
+			m.forward = append(m.forward, m.history[k])
+		}
+		m.screen = m.history[j]
+		m.history = m.history[:j]
+		m.initScreen(true)
+		return m, tea.Batch(tea.ClearScreen, m.screenCmd())
+	}
+	return m.navigate(m.crumbs[i].screen)
+}
+
+// crumbAt returns the index of the breadcrumb drawn at column x, or -1
+func (m *Model) crumbAt(x int) int {
+	for i, c := range m.crumbs {
+		if x >= c.x && x < c.x+c.width {
+			return i
+		}
+	}
+	return -1
+}
+
+// renderTopRow draws the breadcrumb bar, with any confirmation on the right
+func (m *Model) renderTopRow() string {
+	row := ""
+	if len(m.crumbs) > 1 {
+		row = m.renderCrumbs()
+	}
+	if m.notice != "" {
+		gap := max(m.width-lipgloss.Width(row)-len([]rune(m.notice))-2, 1)
+		row += strings.Repeat(" ", gap) + ui.SelectedStyle.Render(m.notice)
+	}
+	return row
+}
+
+// renderCrumbs draws the breadcrumb bar, recording where each crumb lands
+func (m *Model) renderCrumbs() string {
+	var b strings.Builder
+	b.WriteString(" ")
+	x := 1
+	for i := range m.crumbs {
+		c := &m.crumbs[i]
+		if i > 0 {
+			b.WriteString(ui.DimStyle.Render(" › "))
+			x += 3
+		}
+
+		style := ui.DimStyle
+		switch {
+		case i == m.crumbCursor:
+			style = ui.SelectedStyle
+		case i == len(m.crumbs)-1:
+			style = ui.NormalLabelStyle
+		}
+		b.WriteString(style.Render(c.label))
+
+		c.x, c.width = x, len([]rune(c.label))
+		x += c.width
+	}
+	return b.String()
+}
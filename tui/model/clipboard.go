@@ -0,0 +1,153 @@
+package model
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"delica-tui/db"
+	"delica-tui/ui"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// copyMsg asks the terminal to put text on the clipboard
+type copyMsg struct {
+	text    string
+	confirm string // Status line shown once copied
+}
+
+// copyPart copies part data chosen by key: the part number, the row as
+// tab-separated fields, or a multi-line summary
+func copyPart(database *db.DB, partID int, msg tea.KeyMsg) tea.Cmd {
+	part, _ := database.GetPart(partID)
+	if part == nil {
+		return nil
+	}
+
+	var text, what string
+	switch {
+	case ui.IsCopyPartNumber(msg):
+		text, what = part.PartNumber, part.PartNumber
+	case ui.IsCopyRow(msg):
+		text, what = partRow(part), "row for "+part.PartNumber
+	case ui.IsCopySummary(msg):
+		text, what = partSummary(database, part), "summary of "+part.PartNumber
+	default:
+		return nil
+	}
+
+	return func() tea.Msg {
+		return copyMsg{text: text, confirm: "Copied " + what}
+	}
+}
+
+// partRow returns a part's list row as tab-separated fields, for pasting
+// into a spreadsheet
+func partRow(p *db.PartWithDiagram) string {
+	quantity := ""
+	if p.Quantity != nil {
+		quantity = fmt.Sprintf("%d", *p.Quantity)
+	}
+	return strings.Join([]string{
+		deref(p.PNC),
+		p.PartNumber,
+		deref(p.Description),
+		quantity,
+		deref(p.Spec),
+		deref(p.ReplacementPartNumber),
+	}, "\t")
+}
+
+// partSummary describes a part over several lines
+func partSummary(database *db.DB, p *db.PartWithDiagram) string {
+	var b strings.Builder
+	b.WriteString(p.PartNumber)
+	if p.Description != nil {
+		b.WriteString(" " + *p.Description)
+	}
+	b.WriteString("\n")
+
+	field := func(label string, value *string) {
+		if value != nil && *value != "" {
+			fmt.Fprintf(&b, "%s: %s\n", label, *value)
+		}
+	}
+	field("PNC", p.PNC)
+	if p.Quantity != nil {
+		fmt.Fprintf(&b, "Quantity: %d\n", *p.Quantity)
+	}
+	field("Spec", p.Spec)
+	field("Color", p.Color)
+	field("Date Range", p.ModelDateRange)
+	field("Replaced By", p.ReplacementPartNumber)
+
+	if group, _ := database.GetGroup(p.GroupID); group != nil {
+		location := group.Name
+		if p.SubgroupID != nil {
+			if subgroup, _ := database.GetSubgroup(*p.SubgroupID); subgroup != nil {
+				location += " > " + subgroup.Name
+			}
+		}
+		fmt.Fprintf(&b, "Location: %s\n", location)
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// clipboardSequence wraps text in an OSC 52 escape sequence, which sets the
+// clipboard of the terminal the user is sitting at, even over SSH
+func clipboardSequence(text string) string {
+	seq := osc52.New(text)
+	switch {
+	case os.Getenv("TMUX") != "":
+		seq = seq.Tmux()
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		seq = seq.Screen()
+	}
+	return seq.String()
+}
+
+// selectedPartID returns the part open or selected on the current screen,
+// or 0 if there is none
+func (m *Model) selectedPartID() int {
+	var menu *ui.Menu
+	switch m.screen.Type {
+	case ScreenPartDetail:
+		if m.partDetail.part != nil {
+			return m.partDetail.partID
+		}
+		return 0
+	case ScreenSubgroup:
+		menu = m.subgroup.menu
+	case ScreenSearch:
+		menu = m.search.menu
+	case ScreenBookmarks:
+		menu = m.bookmarks.menu
+	case ScreenNotes:
+		menu = m.notes.menu
+	case ScreenOrder:
+		menu = m.order.menu
+	case ScreenJumps:
+		if i := m.jumpList.menu.Cursor; m.jumpList.menu.Selected() != nil && i < len(m.jumpList.jumps) {
+			return m.jumpList.jumps[i].PartID
+		}
+		return 0
+	default:
+		return 0
+	}
+
+	var partID int
+	if item := menu.Selected(); item != nil {
+		fmt.Sscanf(item.ID, "%d", &partID)
+	}
+	return partID
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"path/filepath"
+
+	"delica-tui/bom"
+	"delica-tui/db"
+	"delica-tui/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// exportsDir is where exported part lists are written, under the data directory
+const exportsDir = "exports"
+
+// exportFormats maps the key pressed after e to a BOM format
+var exportFormats = map[string]string{"c": "csv", "j": "json", "m": "md", "h": "html"}
+
+// exportHelp is the footer shown while choosing a format
+const exportHelp = "c csv   j json   m markdown   h html   esc cancel"
+
+// exportParts saves parts as a bill of materials in the exports directory,
+// in one of bom.Formats, and returns its path
+func exportParts(database *db.DB, dataPath, title string, parts []db.PartWithDiagram, format string) (string, error) {
+	b := bom.FromParts(database, dataPath, title, parts)
+	return bom.Save(b, filepath.Join(dataPath, exportsDir), title, format)
+}
+
+// exportBOM saves a BOM in the format chosen by key and returns a status
+// line; other keys cancel with no status
+func exportBOM(build func() (*bom.BOM, error), dataPath string, msg tea.KeyMsg) string {
+	format, ok := exportFormats[msg.String()]
+	if !ok {
+		return ""
+	}
+
+	b, err := build()
+	if err == nil {
+		var path string
+		path, err = bom.Save(b, filepath.Join(dataPath, exportsDir), b.Title, format)
+		if err == nil {
+			return "Exported to " + path
+		}
+	}
+	return ui.ErrorStyle.Render("Export failed: " + err.Error())
+}
@@ -0,0 +1,113 @@
+package model
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"delica-tui/config"
+	"delica-tui/db"
+)
+
+// fitMode is how lists show parts that don't fit the vehicle
+type fitMode string
+
+const (
+	fitDim  fitMode = "dim"  // Listed in grey, with the reason
+	fitHide fitMode = "hide" // Left out, with a count
+	fitAll  fitMode = "all"  // Listed like any other part
+)
+
+// next returns the mode ctrl+f switches to
+func (f fitMode) next() fitMode {
+	switch f {
+	case fitDim:
+		return fitHide
+	case fitHide:
+		return fitAll
+	}
+	return fitDim
+}
+
+func (f fitMode) String() string {
+	switch f {
+	case fitHide:
+		return "Parts that don't fit are hidden"
+	case fitAll:
+		return "Showing every part"
+	}
+	return "Parts that don't fit are dimmed"
+}
+
+// fit checks parts against the vehicle in .env and decides how to list
+// those that don't fit. It also names color codes and spots the van's own.
+type fit struct {
+	vehicle db.Vehicle
+	mode    fitMode
+	colors  map[string]string // Color names from the config
+}
+
+func newFit(cfg *config.Config) fit {
+	mode := fitMode(cfg.Applicability)
+	if mode != fitHide && mode != fitAll {
+		mode = fitDim
+	}
+	vehicle := db.NewVehicle(os.Getenv("MANUFACTURE_DATE"), os.Getenv("VEHICLE_NAME"), os.Getenv("VEHICLE_SPEC"))
+	vehicle.Exterior = os.Getenv("EXTERIOR_CODE")
+	vehicle.Interior = os.Getenv("INTERIOR_CODE")
+
+	colors := make(map[string]string)
+	for code, name := range cfg.Colors {
+		colors[strings.ToUpper(code)] = name
+	}
+
+	return fit{vehicle: vehicle, mode: mode, colors: colors}
+}
+
+// check reports whether a part should be listed as fitting, and if not,
+// why. Every part fits when the mode is all.
+func (f fit) check(p db.Part) (bool, string) {
+	if f.mode == fitAll {
+		return true, ""
+	}
+	return f.vehicle.Fits(p)
+}
+
+// hides reports whether parts that don't fit are left out of lists
+func (f fit) hides() bool {
+	return f.mode == fitHide
+}
+
+// unfitHint adds why a part doesn't fit to its menu hint
+func unfitHint(hint, reason string) string {
+	if hint == "" {
+		return "doesn't fit: " + reason
+	}
+	return hint + " - doesn't fit: " + reason
+}
+
+// unfitCount describes how many listed parts don't fit, for a list header
+func unfitCount(n int, f fit) string {
+	if f.hides() {
+		return fmt.Sprintf("%d hidden", n)
+	}
+	return fmt.Sprintf("%d don't fit", n)
+}
+
+// color describes a part's color codes with their names from the config,
+// e.g. "W09M Sophia White, 57A", and reports whether one of them is the van's.
+// It returns "" for parts with no color.
+func (f fit) color(p db.Part) (string, bool) {
+	if p.Color == nil {
+		return "", false
+	}
+	var names []string
+	for _, code := range db.ColorCodes(*p.Color) {
+		if name := db.ColorName(code, f.colors); name != "" {
+			code += " " + name
+		}
+		names = append(names, code)
+	}
+	code, _ := f.vehicle.ColorMatch(p)
+	return strings.Join(names, ", "), code != ""
+}
@@ -3,7 +3,9 @@ package model
 import (
 	"strings"
 
+	"delica-tui/bom"
 	"delica-tui/db"
+	"delica-tui/image"
 	"delica-tui/ui"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -16,9 +18,17 @@ type GroupModel struct {
 	group     *db.Group
 	subgroups []db.Subgroup
 	menu      *ui.Menu
+	dataPath  string
+	exporting bool   // e was pressed; the next key picks the export format
+	status    string // Result of the last export
+
+	// Diagram previews of the selected subgroup, shared with the model, and
+	// the one last drawn
+	thumbs thumbnails
+	shown  uint32
 }
 
-func NewGroupModel(database *db.DB, groupID string) *GroupModel {
+func NewGroupModel(database *db.DB, groupID string, dataPath string, thumbs thumbnails) *GroupModel {
 	group, _ := database.GetGroup(groupID)
 	subgroups, _ := database.GetSubgroups(groupID)
 
@@ -33,12 +43,22 @@ func NewGroupModel(database *db.DB, groupID string) *GroupModel {
 		group:     group,
 		subgroups: subgroups,
 		menu:      ui.NewMenu(items),
+		dataPath:  dataPath,
+		thumbs:    thumbs,
 	}
 }
 
 func (m *GroupModel) Update(msg tea.Msg) (*GroupModel, tea.Cmd, *Screen) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		m.status = ""
+		if m.exporting {
+			m.exporting = false
+			m.status = exportBOM(func() (*bom.BOM, error) {
+				return bom.ForGroup(m.db, m.dataPath, m.groupID)
+			}, m.dataPath, msg)
+			return m, nil, nil
+		}
 		if ui.IsUp(msg) {
 			m.menu.Up()
 		}
@@ -46,16 +66,58 @@ func (m *GroupModel) Update(msg tea.Msg) (*GroupModel, tea.Cmd, *Screen) {
 			m.menu.Down()
 		}
 		if ui.IsEnter(msg) {
-			if item := m.menu.Selected(); item != nil {
-				s := SubgroupScreen(item.ID)
-				return m, nil, &s
-			}
+			return m.openSelected()
+		}
+		if ui.IsExport(msg) && len(m.subgroups) > 0 {
+			m.exporting = true
+		}
+		if ui.IsContactSheet(msg) && len(m.subgroups) > 0 {
+			s := SheetScreen(m.groupID)
+			return m, nil, &s
 		}
+
+	case tea.MouseMsg:
+		if m.menu.Mouse(msg) {
+			return m.openSelected()
+		}
+	}
+	return m, m.loadThumbnail(), nil
+}
+
+// loadThumbnail starts loading the selected subgroup's diagram preview
+func (m *GroupModel) loadThumbnail() tea.Cmd {
+	if item := m.menu.Selected(); item != nil {
+		return m.thumbs.load(m.db, m.dataPath, item.ID)
+	}
+	return nil
+}
+
+// thumbnail returns the selected subgroup's diagram preview, and false
+// while it is loading
+func (m *GroupModel) thumbnail() (*thumbnail, bool) {
+	item := m.menu.Selected()
+	if item == nil {
+		return &thumbnail{}, true
+	}
+	thumb, ok := m.thumbs[item.ID]
+	return thumb, ok && thumb != nil
+}
+
+// ImageID returns the ID of the preview last drawn, if any
+func (m *GroupModel) ImageID() uint32 {
+	return m.shown
+}
+
+// openSelected navigates to the item under the cursor
+func (m *GroupModel) openSelected() (*GroupModel, tea.Cmd, *Screen) {
+	if item := m.menu.Selected(); item != nil {
+		s := SubgroupScreen(item.ID)
+		return m, nil, &s
 	}
 	return m, nil, nil
 }
 
-func (m *GroupModel) View(width, height int) string {
+func (m *GroupModel) View(width, height int, layout ui.SplitLayout) string {
 	if width == 0 {
 		width = 80
 	}
@@ -77,31 +139,73 @@ func (m *GroupModel) View(width, height int) string {
 		splitHeight = 10
 	}
 
-	leftContent := m.renderLeftPane(splitHeight)
-	rightContent := m.renderRightPane(splitHeight)
+	leftPane, rightPane := layout.Panes(width-2, splitHeight)
+	leftContent := m.renderLeftPane(leftPane)
+	rightContent := m.renderRightPane(rightPane)
 
-	split := ui.RenderSplitPane(leftContent, rightContent, width-2, splitHeight)
+	split := ui.RenderSplitPane(leftContent, rightContent, width-2, splitHeight, layout)
 
-	return header + "\n" + split
+	// Clear the previous subgroup's preview, then draw this one over the
+	// placeholder lines, below the blank line and diagram ID
+	var result strings.Builder
+	result.WriteString(header + "\n")
+
+	thumb, ok := m.thumbnail()
+	var id uint32
+	if ok && thumb.img != nil && leftPane.Width > 0 {
+		id = thumb.img.ID()
+	}
+	if m.shown != 0 && m.shown != id {
+		result.WriteString(image.Clear(m.shown))
+	}
+	m.shown = id
+
+	if id != 0 {
+		cols, rows := thumb.img.Fit(leftPane.Width, leftPane.Height-2)
+		result.WriteString("\x1b7")   // Save cursor position
+		result.WriteString("  ")      // Left padding (matches split pane margin)
+		result.WriteString("\x1b[2B") // Move cursor down 2 lines (past diagram ID)
+		result.WriteString(thumb.img.RenderSize(cols, rows))
+		result.WriteString("\x1b8") // Restore cursor position
+	}
+	result.WriteString(split)
+
+	return result.String()
 }
 
-func (m *GroupModel) renderLeftPane(height int) string {
-	var lines []string
+// renderLeftPane shows the selected subgroup's diagram, leaving room for
+// the image drawn by View
+func (m *GroupModel) renderLeftPane(pane ui.Rect) string {
+	lines := []string{""}
 
-	// Group info (title is already in header, so just show instructions)
-	lines = append(lines, "")
-	lines = append(lines, ui.DimStyle.Render("Select a subgroup to"))
-	lines = append(lines, ui.DimStyle.Render("view parts and diagrams"))
+	thumb, ok := m.thumbnail()
+	switch {
+	case !ok:
+		lines = append(lines, ui.DimStyle.Render("Loading diagram..."))
+	case thumb.img != nil:
+		lines = append(lines, ui.DimStyle.Render(thumb.diagramID))
+		_, rows := thumb.img.Fit(pane.Width, pane.Height-2)
+		for i := 0; i < rows; i++ {
+			lines = append(lines, "")
+		}
+	case thumb.err != "":
+		lines = append(lines, ui.ErrorStyle.Width(pane.Width).Render(thumb.err))
+	case m.menu.Selected() != nil:
+		lines = append(lines, ui.DimStyle.Render("No diagram available"))
+	default:
+		lines = append(lines, ui.DimStyle.Render("Select a subgroup to"))
+		lines = append(lines, ui.DimStyle.Render("view parts and diagrams"))
+	}
 
 	// Pad to fill height
-	for len(lines) < height {
+	for len(lines) < pane.Height {
 		lines = append(lines, "")
 	}
 
 	return strings.Join(lines, "\n")
 }
 
-func (m *GroupModel) renderRightPane(height int) string {
+func (m *GroupModel) renderRightPane(pane ui.Rect) string {
 	var b strings.Builder
 
 	// Header - show group name
@@ -114,7 +218,7 @@ func (m *GroupModel) renderRightPane(height int) string {
 	b.WriteString(ui.DimStyle.Render("─────────────────────────────────"))
 
 	// Adjust menu visible items based on available height (max 15)
-	menuHeight := height - 5
+	menuHeight := pane.Height - 5
 	if menuHeight < 5 {
 		menuHeight = 5
 	}
@@ -134,11 +238,19 @@ func (m *GroupModel) renderRightPane(height int) string {
 	if len(m.subgroups) == 0 {
 		b.WriteString(ui.DimStyle.Render("No subgroups found"))
 	} else {
+		m.menu.SetOrigin(pane.X, splitTop+pane.Y+strings.Count(b.String(), "\n"), pane.Width)
 		b.WriteString(m.menu.View())
 	}
 
+	help := "↑↓ navigate   enter select   f filter   g sheet   e export"
+	switch {
+	case m.exporting:
+		help = exportHelp
+	case m.status != "":
+		help = m.status
+	}
 	b.WriteString("\n\n")
-	b.WriteString(ui.DimStyle.Render("↑↓ navigate   enter select"))
+	b.WriteString(ui.DimStyle.Render(help))
 
 	return b.String()
 }
@@ -27,15 +27,21 @@ func getVehicleInfo() (name, frame, exterior, interior, date string) {
 type HomeModel struct {
 	db            *db.DB
 	groups        []db.Group
+	saved         []db.SavedSearch
 	bookmarkCount int
 	noteCount     int
+	orderCount    int
 	menu          *ui.Menu
+	menuOrigin    ui.Rect // Screen position of the menu, for mouse clicks
+	menuRows      []int   // Item drawn on each row of the menu (-1 for none)
 }
 
 func NewHomeModel(database *db.DB) *HomeModel {
 	groups, _ := database.GetGroups()
 	bookmarkCount, _ := database.GetBookmarkCount()
 	noteCount, _ := database.GetNoteCount()
+	orderCount, _ := database.GetOrderCount()
+	saved, _ := database.GetSavedSearches()
 
 	// Build menu items
 	var items []ui.MenuItem
@@ -55,6 +61,23 @@ func NewHomeModel(database *db.DB) *HomeModel {
 	}
 	items = append(items, ui.MenuItem{ID: "__notes__", Label: "# Notes", Hint: noteHint})
 
+	orderHint := ""
+	if orderCount > 0 {
+		orderHint = fmt.Sprintf("%d parts", orderCount)
+	}
+	items = append(items, ui.MenuItem{ID: "__order__", Label: "+ Order List", Hint: orderHint})
+	items = append(items, ui.MenuItem{ID: "__stats__", Label: "= Catalog Stats", Hint: statsHint(database)})
+
+	// Saved searches, with a live count of their results
+	for i, s := range saved {
+		count, _ := database.CountSearch(s.Query, s.Filter)
+		hint := fmt.Sprintf("%d results", count)
+		if s.Query != s.Name {
+			hint = fmt.Sprintf("\"%s\" - %s", s.Query, hint)
+		}
+		items = append(items, ui.MenuItem{ID: fmt.Sprintf("__saved__%d", i), Label: "/ " + s.Name, Hint: hint})
+	}
+
 	// Separator (empty item that we'll skip in navigation)
 	items = append(items, ui.MenuItem{ID: "__separator__", Label: ""})
 
@@ -66,8 +89,10 @@ func NewHomeModel(database *db.DB) *HomeModel {
 	return &HomeModel{
 		db:            database,
 		groups:        groups,
+		saved:         saved,
 		bookmarkCount: bookmarkCount,
 		noteCount:     noteCount,
+		orderCount:    orderCount,
 		menu:          ui.NewMenu(items),
 	}
 }
@@ -76,44 +101,112 @@ func (m *HomeModel) Update(msg tea.Msg) (*HomeModel, tea.Cmd, *Screen) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		if ui.IsUp(msg) {
-			m.menu.Up()
-			// Skip separator
-			if m.menu.Selected() != nil && m.menu.Selected().ID == "__separator__" {
-				m.menu.Up()
-			}
+			m.up()
 		}
 		if ui.IsDown(msg) {
-			m.menu.Down()
-			// Skip separator
-			if m.menu.Selected() != nil && m.menu.Selected().ID == "__separator__" {
-				m.menu.Down()
-			}
+			m.down()
 		}
 		if ui.IsEnter(msg) {
-			if item := m.menu.Selected(); item != nil {
-				switch item.ID {
-				case "__search__":
-					s := SearchScreen("")
-					return m, nil, &s
-				case "__bookmarks__":
-					s := BookmarksScreen()
-					return m, nil, &s
-				case "__notes__":
-					s := NotesScreen()
-					return m, nil, &s
-				case "__separator__":
-					// Do nothing
-				default:
-					s := GroupScreen(item.ID)
-					return m, nil, &s
-				}
+			return m.openSelected()
+		}
+		if ui.IsDelete(msg) {
+			m.removeSelected()
+		}
+
+	case tea.MouseMsg:
+		switch {
+		case ui.IsWheelUp(msg):
+			m.up()
+		case ui.IsWheelDown(msg):
+			m.down()
+		case ui.IsClick(msg) && m.menuOrigin.Contains(msg.X, msg.Y):
+			// The home menu is drawn unwindowed
+			if row := msg.Y - m.menuOrigin.Y; row < len(m.menuRows) && m.menuRows[row] >= 0 {
+				m.menu.Cursor = m.menuRows[row]
+				return m.openSelected()
+			}
+		}
+	}
+	return m, nil, nil
+}
+
+func (m *HomeModel) up() {
+	m.menu.Up()
+	// Skip separator
+	if m.menu.Selected() != nil && m.menu.Selected().ID == "__separator__" {
+		m.menu.Up()
+	}
+}
+
+func (m *HomeModel) down() {
+	m.menu.Down()
+	// Skip separator
+	if m.menu.Selected() != nil && m.menu.Selected().ID == "__separator__" {
+		m.menu.Down()
+	}
+}
+
+// openSelected navigates to the item under the cursor
+func (m *HomeModel) openSelected() (*HomeModel, tea.Cmd, *Screen) {
+	if item := m.menu.Selected(); item != nil {
+		switch item.ID {
+		case "__search__":
+			s := SearchScreen("")
+			return m, nil, &s
+		case "__bookmarks__":
+			s := BookmarksScreen()
+			return m, nil, &s
+		case "__notes__":
+			s := NotesScreen()
+			return m, nil, &s
+		case "__order__":
+			s := OrderScreen()
+			return m, nil, &s
+		case "__stats__":
+			s := StatsScreen()
+			return m, nil, &s
+		case "__separator__":
+			// Do nothing
+		default:
+			if s := m.selectedSaved(); s != nil {
+				screen := FilteredSearchScreen(s.Query, s.Filter)
+				return m, nil, &screen
 			}
+			s := GroupScreen(item.ID)
+			return m, nil, &s
 		}
 	}
 	return m, nil, nil
 }
 
-func (m *HomeModel) View(width, height int) string {
+// selectedSaved returns the saved search under the cursor, if any
+func (m *HomeModel) selectedSaved() *db.SavedSearch {
+	item := m.menu.Selected()
+	if item == nil {
+		return nil
+	}
+	var i int
+	if _, err := fmt.Sscanf(item.ID, "__saved__%d", &i); err != nil || i >= len(m.saved) {
+		return nil
+	}
+	return &m.saved[i]
+}
+
+// removeSelected deletes the saved search under the cursor
+func (m *HomeModel) removeSelected() {
+	s := m.selectedSaved()
+	if s == nil || m.db.RemoveSavedSearch(s.ID) != nil {
+		return
+	}
+	cursor := m.menu.Cursor
+	*m = *NewHomeModel(m.db)
+	m.menu.Cursor = min(cursor, len(m.menu.Items)-1)
+	if m.menu.Selected().ID == "__separator__" {
+		m.up()
+	}
+}
+
+func (m *HomeModel) View(width, height int, layout ui.SplitLayout) string {
 	if width == 0 {
 		width = 80
 	}
@@ -135,10 +228,11 @@ func (m *HomeModel) View(width, height int) string {
 		splitHeight = 10
 	}
 
-	leftContent := m.renderLeftPane(splitHeight)
-	rightContent := m.renderRightPane(splitHeight)
+	leftPane, rightPane := layout.Panes(width-2, splitHeight)
+	leftContent := m.renderLeftPane(leftPane.Height)
+	rightContent := m.renderRightPane(rightPane)
 
-	split := ui.RenderSplitPane(leftContent, rightContent, width-2, splitHeight)
+	split := ui.RenderSplitPane(leftContent, rightContent, width-2, splitHeight, layout)
 
 	return header + "\n" + split
 }
@@ -164,11 +258,11 @@ func (m *HomeModel) renderLeftPane(height int) string {
 	return strings.Join(lines, "\n")
 }
 
-func (m *HomeModel) renderRightPane(height int) string {
+func (m *HomeModel) renderRightPane(pane ui.Rect) string {
 	var b strings.Builder
 
 	// Adjust menu visible items based on available height (max 15)
-	menuHeight := height - 5
+	menuHeight := pane.Height - 5
 	if menuHeight < 5 {
 		menuHeight = 5
 	}
@@ -185,19 +279,39 @@ func (m *HomeModel) renderRightPane(height int) string {
 	}
 
 	// Menu
+	m.menuOrigin = ui.Rect{
+		X:      pane.X,
+		Y:      splitTop + pane.Y + strings.Count(b.String(), "\n"),
+		Width:  pane.Width,
+		Height: len(m.menu.Items),
+	}
 	b.WriteString(m.renderMenuWithSeparator())
 
 	b.WriteString("\n\n")
-	b.WriteString(ui.DimStyle.Render("↑↓ navigate   enter select   / search"))
+	help := "↑↓ navigate   enter select   f filter   / search"
+	if m.selectedSaved() != nil {
+		help += "   x delete"
+	}
+	b.WriteString(ui.DimStyle.Render(help))
 
 	return b.String()
 }
 
 func (m *HomeModel) renderMenuWithSeparator() string {
-	var b strings.Builder
+	var lines []string
+	m.menuRows = m.menuRows[:0]
+	if line := m.menu.FilterLine(); line != "" {
+		lines = append(lines, line)
+		m.menuRows = append(m.menuRows, -1)
+	}
+
 	for i, item := range m.menu.Items {
+		if !m.menu.Shown(i) {
+			continue
+		}
 		if item.ID == "__separator__" {
-			b.WriteString("\n")
+			lines = append(lines, "")
+			m.menuRows = append(m.menuRows, -1)
 			continue
 		}
 
@@ -205,19 +319,26 @@ func (m *HomeModel) renderMenuWithSeparator() string {
 
 		var line string
 		if isSelected {
-			line = ui.SelectedStyle.Render("> ") + ui.SelectedLabelStyle.Render(strings.ToUpper(item.Label))
+			line = ui.SelectedStyle.Render("> ") + m.menu.RenderLabel(i, ui.SelectedLabelStyle)
 		} else {
-			line = "  " + ui.NormalLabelStyle.Render(strings.ToUpper(item.Label))
+			line = "  " + m.menu.RenderLabel(i, ui.NormalLabelStyle)
 		}
 
 		if item.Hint != "" {
-			line += ui.DimStyle.Render(" " + item.Hint)
+			line += ui.DimStyle.Render(" ") + m.menu.RenderHint(i)
 		}
 
-		b.WriteString(line)
-		if i < len(m.menu.Items)-1 {
-			b.WriteString("\n")
-		}
+		lines = append(lines, line)
+		m.menuRows = append(m.menuRows, i)
 	}
-	return b.String()
+	return strings.Join(lines, "\n")
+}
+
+// statsHint warns on the home menu when the scrape didn't finish
+func statsHint(database *db.DB) string {
+	progress, _ := database.GetScrapeProgress()
+	if progress == nil || progress.Failed == 0 && progress.Pending == 0 {
+		return ""
+	}
+	return fmt.Sprintf("scrape incomplete: %d failed, %d pending", progress.Failed, progress.Pending)
 }
@@ -0,0 +1,201 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"delica-tui/db"
+	"delica-tui/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// jumpLimit caps how many recently visited screens are remembered
+const jumpLimit = 30
+
+// JumpsModel lists recently visited parts and subgroups, newest first.
+type JumpsModel struct {
+	db    *db.DB
+	jumps []Screen
+	menu  *ui.Menu
+}
+
+func NewJumpsModel(database *db.DB, jumps []Screen) *JumpsModel {
+	var items []ui.MenuItem
+	for _, s := range jumps {
+		label, hint := jumpLabel(database, s)
+		items = append(items, ui.MenuItem{ID: s.key(), Label: label, Hint: hint})
+	}
+
+	menu := ui.NewMenu(items)
+	menu.LabelWidth = 24
+
+	return &JumpsModel{
+		db:    database,
+		jumps: jumps,
+		menu:  menu,
+	}
+}
+
+// jumpLabel describes a visited part or subgroup
+func jumpLabel(database *db.DB, s Screen) (label, hint string) {
+	switch s.Type {
+	case ScreenPartDetail:
+		part, _ := database.GetPart(s.PartID)
+		if part == nil {
+			return fmt.Sprintf("Part %d", s.PartID), ""
+		}
+		if part.Description != nil {
+			hint = *part.Description
+		}
+		return part.PartNumber, hint
+	case ScreenSubgroup:
+		subgroup, _ := database.GetSubgroup(s.SubgroupID)
+		if subgroup == nil {
+			return s.SubgroupID, ""
+		}
+		if group, _ := database.GetGroup(subgroup.GroupID); group != nil {
+			hint = group.Name
+		}
+		return subgroup.Name, hint
+	}
+	return s.Type.String(), ""
+}
+
+// recordJump adds a screen to the front of the jump list, if it is a part
+// or subgroup, removing any earlier visit to the same place
+func recordJump(jumps []Screen, s Screen) []Screen {
+	if s.Type != ScreenPartDetail && s.Type != ScreenSubgroup {
+		return jumps
+	}
+
+	// Jumps open fresh, without the selection of the visit
+	s = Screen{Type: s.Type, SubgroupID: s.SubgroupID, PartID: s.PartID}
+
+	out := []Screen{s}
+	for _, j := range jumps {
+		if j != s && len(out) < jumpLimit {
+			out = append(out, j)
+		}
+	}
+	return out
+}
+
+func (m *JumpsModel) Update(msg tea.Msg) (*JumpsModel, tea.Cmd, *Screen) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if ui.IsUp(msg) {
+			m.menu.Up()
+		}
+		if ui.IsDown(msg) {
+			m.menu.Down()
+		}
+		if ui.IsEnter(msg) {
+			return m.openSelected()
+		}
+
+	case tea.MouseMsg:
+		if m.menu.Mouse(msg) {
+			return m.openSelected()
+		}
+	}
+	return m, nil, nil
+}
+
+// openSelected navigates to the item under the cursor
+func (m *JumpsModel) openSelected() (*JumpsModel, tea.Cmd, *Screen) {
+	if m.menu.Selected() == nil {
+		return m, nil, nil
+	}
+	s := m.jumps[m.menu.Cursor]
+	return m, nil, &s
+}
+
+func (m *JumpsModel) View(width, height int, layout ui.SplitLayout) string {
+	if width == 0 {
+		width = 80
+	}
+	if height == 0 {
+		height = 24
+	}
+
+	// Header
+	headerStyle := lipgloss.NewStyle().
+		Width(width - 2).
+		Padding(1, 1, 0, 1).
+		Align(lipgloss.Right)
+
+	header := headerStyle.Render(ui.DimStyle.Render("esc back"))
+
+	// Split pane content
+	splitHeight := height - 5
+	if splitHeight < 10 {
+		splitHeight = 10
+	}
+
+	leftPane, rightPane := layout.Panes(width-2, splitHeight)
+	leftContent := m.renderLeftPane(leftPane.Height)
+	rightContent := m.renderRightPane(rightPane)
+
+	split := ui.RenderSplitPane(leftContent, rightContent, width-2, splitHeight, layout)
+
+	return header + "\n" + split
+}
+
+func (m *JumpsModel) renderLeftPane(height int) string {
+	var lines []string
+
+	lines = append(lines, ui.HeaderStyle.Render("JUMP LIST"))
+	lines = append(lines, "")
+	lines = append(lines, fmt.Sprintf("%d places this session", len(m.jumps)))
+	lines = append(lines, "")
+	lines = append(lines, ui.DimStyle.Render("Parts and subgroups"))
+	lines = append(lines, ui.DimStyle.Render("you have visited"))
+
+	// Pad to fill height
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m *JumpsModel) renderRightPane(pane ui.Rect) string {
+	var b strings.Builder
+
+	// Header
+	b.WriteString(ui.HeaderStyle.Render("RECENTLY VISITED"))
+	b.WriteString("\n")
+	b.WriteString(ui.DimStyle.Render("─────────────────────────────────"))
+
+	// Adjust menu visible items based on available height (max 15)
+	menuHeight := pane.Height - 5
+	if menuHeight < 5 {
+		menuHeight = 5
+	}
+	if menuHeight > 15 {
+		menuHeight = 15
+	}
+	m.menu.MaxVisibleItems = menuHeight
+
+	// One less blank line if menu scrolls (to account for scroll indicator)
+	if len(m.menu.Items) > m.menu.MaxVisibleItems {
+		b.WriteString("\n")
+	} else {
+		b.WriteString("\n\n")
+	}
+
+	// Menu
+	if len(m.jumps) == 0 {
+		b.WriteString(ui.DimStyle.Render("Nothing visited yet"))
+	} else {
+		m.menu.SetOrigin(pane.X, splitTop+pane.Y+strings.Count(b.String(), "\n"), pane.Width)
+		b.WriteString(m.menu.View())
+	}
+
+	b.WriteString("\n\n")
+	b.WriteString(ui.DimStyle.Render("↑↓ navigate   enter select   f filter"))
+
+	return b.String()
+}
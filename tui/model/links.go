@@ -0,0 +1,76 @@
+package model
+
+import (
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"delica-tui/config"
+	"delica-tui/db"
+)
+
+// partLink is an external link for a part, from a link provider
+type partLink struct {
+	label string
+	url   string
+}
+
+var placeholderRe = regexp.MustCompile(`\{(\w+)\}`)
+
+// partLinks fills in each provider's URL template for a part. Providers that
+// use a field the part doesn't have (a PNC, say) are left out.
+func partLinks(providers []config.LinkProvider, part *db.PartWithDiagram) []partLink {
+	fields := linkFields(part)
+
+	var links []partLink
+	for _, p := range providers {
+		complete := true
+		u := placeholderRe.ReplaceAllStringFunc(p.URL, func(ph string) string {
+			value := fields[ph[1:len(ph)-1]]
+			if value == "" {
+				complete = false
+			}
+			// Escape values for URLs, keeping the slashes in subgroup IDs
+			return strings.ReplaceAll(url.PathEscape(value), "%2F", "/")
+		})
+		if complete {
+			links = append(links, partLink{label: p.Label, url: u})
+		}
+	}
+	return links
+}
+
+// linkFields returns the values for link placeholders. {replacement} falls
+// back to the part number, and the frame fields to the defaults for the
+// vehicle the catalog was scraped for.
+func linkFields(part *db.PartWithDiagram) map[string]string {
+	fields := map[string]string{
+		"part_number": part.PartNumber,
+		"replacement": part.PartNumber,
+		"frame_name":  envOr("FRAME_NAME", "pd6w"),
+		"trim_code":   envOr("TRIM_CODE", "hseue9"),
+		"frame_no":    envOr("FRAME_NO", "PD6W-0500900"),
+	}
+	if part.ReplacementPartNumber != nil {
+		fields["replacement"] = *part.ReplacementPartNumber
+	}
+	if part.PNC != nil {
+		fields["pnc"] = *part.PNC
+	}
+	if part.SubgroupID != nil {
+		fields["subgroup"] = *part.SubgroupID
+	}
+	if part.DetailPageID != nil {
+		fields["detail_page"] = *part.DetailPageID
+	}
+	return fields
+}
+
+// envOr returns an environment variable, or fallback if it is unset
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"testing"
+
+	"delica-tui/config"
+	"delica-tui/db"
+)
+
+func TestPartLinks(t *testing.T) {
+	for _, key := range []string{"FRAME_NAME", "TRIM_CODE", "FRAME_NO"} {
+		t.Setenv(key, "")
+	}
+
+	pnc := "11101"
+	subgroup := "engine/cylinder-head"
+	replacement := "MD999999"
+	full := &db.PartWithDiagram{Part: db.Part{
+		PartNumber:            "MD123456",
+		PNC:                   &pnc,
+		SubgroupID:            &subgroup,
+		ReplacementPartNumber: &replacement,
+	}}
+	bare := &db.PartWithDiagram{Part: db.Part{PartNumber: "MD 12/34"}}
+
+	tests := []struct {
+		name string
+		url  string
+		part *db.PartWithDiagram
+		want string // "" if the link is left out
+	}{
+		{"part number", "https://shop.example/search?q={part_number}", full, "https://shop.example/search?q=MD123456"},
+		{"escaped", "https://shop.example/p/{part_number}", bare, "https://shop.example/p/MD%2012/34"},
+		{"replacement", "https://shop.example/{replacement}", full, "https://shop.example/MD999999"},
+		{"replacement falls back", "https://shop.example/{replacement}", bare, "https://shop.example/MD%2012/34"},
+		{"subgroup keeps slashes", "https://epc.example/{frame_name}/{subgroup}#{pnc}", full,
+			"https://epc.example/pd6w/engine/cylinder-head#11101"},
+		{"frame defaults", "https://epc.example/{frame_no}/{trim_code}", bare, "https://epc.example/PD6W-0500900/hseue9"},
+		{"missing field", "https://epc.example/{pnc}", bare, ""},
+		{"unknown placeholder", "https://epc.example/{colour}", full, ""},
+		{"no placeholders", "https://example.com/", bare, "https://example.com/"},
+	}
+	for _, tt := range tests {
+		links := partLinks([]config.LinkProvider{{Label: tt.name, URL: tt.url}}, tt.part)
+		got := ""
+		if len(links) == 1 {
+			got = links[0].url
+		}
+		if len(links) > 1 || got != tt.want {
+			t.Errorf("%s: partLinks(%q) = %+v, want %q", tt.name, tt.url, links, tt.want)
+		}
+	}
+}
+
+func TestPartLinksFrameFromEnv(t *testing.T) {
+	t.Setenv("FRAME_NAME", "pd8w")
+	part := &db.PartWithDiagram{Part: db.Part{PartNumber: "MD123456"}}
+	links := partLinks([]config.LinkProvider{{Label: "EPC", URL: "https://epc.example/{frame_name}"}}, part)
+	if len(links) != 1 || links[0].url != "https://epc.example/pd8w" || links[0].label != "EPC" {
+		t.Errorf("partLinks = %+v, want the EPC link for pd8w", links)
+	}
+}
@@ -2,7 +2,10 @@ package model
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
+	"delica-tui/config"
 	"delica-tui/db"
 	"delica-tui/image"
 	"delica-tui/ui"
@@ -10,11 +13,23 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// splitTop is the number of rows above the split pane on every screen
+// (the header hint or blank top margin)
+const splitTop = 2
+
 type Model struct {
 	db       *db.DB
 	dataPath string
+	config   *config.Config
 	screen   Screen
 	history  []Screen
+	forward  []Screen // Screens left by going back, for going forward again
+	jumps    []Screen // Recently visited parts and subgroups, newest first
+
+	// Breadcrumb trail for the current screen, and the focused crumb
+	// (-1 when the bar isn't focused)
+	crumbs      []crumb
+	crumbCursor int
 
 	// Screen models
 	home       *HomeModel
@@ -24,6 +39,10 @@ type Model struct {
 	search     *SearchModel
 	bookmarks  *BookmarksModel
 	notes      *NotesModel
+	jumpList   *JumpsModel
+	order      *OrderModel
+	sheet      *SheetModel
+	stats      *StatsModel
 
 	// Terminal size
 	width  int
@@ -31,20 +50,97 @@ type Model struct {
 
 	// Image to clear on next render
 	pendingImageClear uint32
+
+	// Clipboard escape sequence to send on next render
+	pendingCopy string
+
+	// Confirmation shown at the top right until the next key
+	notice string
+
+	// Layout changes, counted so only the last of a run of keys saves,
+	// and whether any are unsaved
+	layoutChanges int
+	layoutUnsaved bool
+
+	// Which parts fit the vehicle, and how lists show the rest
+	fit fit
+
+	// Diagram previews, kept for the session
+	thumbs thumbnails
+
+	// Subgroup screens next to the current one, for ] and [
+	siblings siblings
+
+	// Command palette drawn over the screen, when open
+	palette *paletteModel
+
+	// The theme "auto" stands for, from the terminal's background
+	autoTheme ui.Theme
 }
 
-func New(database *db.DB, dataPath string) *Model {
+func New(database *db.DB, dataPath string, cfg *config.Config) *Model {
 	m := &Model{
 		db:       database,
 		dataPath: dataPath,
+		config:   cfg,
 		screen:   HomeScreen(),
+		fit:      newFit(cfg),
+		thumbs:   make(thumbnails),
+		siblings: make(siblings),
+	}
+	// Ask the terminal for its background now, before Bubble Tea reads input
+	m.autoTheme = detectTheme()
+	if ui.NoColor() {
+		ui.SetTheme(ui.MonoTheme)
+	} else {
+		ui.SetTheme(resolveTheme(cfg, cfg.Theme, m.autoTheme))
 	}
 	m.home = NewHomeModel(database)
+	m.updateCrumbs()
 	return m
 }
 
+// StartAt opens the app on a screen other than Home. The levels above it
+// (Home, its group and subgroup) go into history, so esc walks back up.
+func (m *Model) StartAt(to Screen) {
+	m.history = []Screen{HomeScreen()}
+
+	// The group and subgroup above the target, and the item selected in
+	// each on the way down
+	var groupID, subgroupID, selected string
+	switch to.Type {
+	case ScreenPartDetail:
+		if part, _ := m.db.GetPart(to.PartID); part != nil {
+			groupID = part.GroupID
+			if part.SubgroupID != nil {
+				subgroupID = *part.SubgroupID
+				selected = subgroupID
+			}
+		}
+	case ScreenSubgroup:
+		if subgroup, _ := m.db.GetSubgroup(to.SubgroupID); subgroup != nil {
+			groupID = subgroup.GroupID
+			selected = subgroup.ID
+		}
+	}
+
+	if groupID != "" {
+		group := GroupScreen(groupID)
+		group.ItemID = selected
+		m.history = append(m.history, group)
+	}
+	if subgroupID != "" {
+		subgroup := SubgroupScreen(subgroupID)
+		subgroup.ItemID = fmt.Sprintf("%d", to.PartID)
+		m.history = append(m.history, subgroup)
+	}
+
+	m.screen = to
+	m.initScreen(false)
+}
+
 func (m *Model) Init() tea.Cmd {
-	return nil
+	return m.screenCmd()
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -54,19 +150,139 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		return m, nil
 
+	case clearImageMsg:
+		m.pendingImageClear = uint32(msg)
+		return m, nil
+
+	case layoutSaveMsg:
+		if msg.change == m.layoutChanges {
+			m.saveLayout()
+		}
+		return m, nil
+
+	case copyMsg:
+		m.pendingCopy = clipboardSequence(msg.text)
+		m.notice = msg.confirm
+		return m, nil
+
+	case thumbnailMsg:
+		// Kept even if the screen that asked for it has gone
+		m.thumbs[msg.subgroupID] = msg.thumb
+		return m, m.screenCmd()
+
+	case prefetchMsg:
+		return m, m.buildSiblings(msg)
+
+	case diagramMsg:
+		m.setDiagram(msg)
+		return m, nil
+
 	case tea.KeyMsg:
+		m.notice = ""
+
+		if m.palette != nil {
+			return m.updatePalette(msg)
+		}
+		if m.crumbCursor >= 0 {
+			return m.updateCrumbKeys(msg)
+		}
+		if ui.IsPalette(msg) && !m.modalActive() {
+			return m.openPalette()
+		}
+
+		// Type-to-filter in the screen's list
+		if menu := m.filterMenu(); menu != nil {
+			if menu.Filtering() && menu.UpdateFilter(msg) {
+				return m, m.screenCmd()
+			}
+			if ui.IsBack(msg) && menu.Filtered() {
+				menu.ClearFilter()
+				return m, m.screenCmd()
+			}
+			if ui.IsFilter(msg) && !m.modalActive() {
+				menu.StartFilter()
+				return m, nil
+			}
+		}
+
 		// Global keys
-		if ui.IsQuit(msg) {
+		if ui.IsQuit(msg) && !m.inputFocused() {
 			// Clear all images before quitting by printing directly
 			fmt.Print(image.ClearAll())
+			m.saveLayout()
 			return m, tea.Quit
 		}
-		if ui.IsBack(msg) {
+		if ui.IsBack(msg) && !m.modalActive() {
 			return m.goBack()
 		}
-		if ui.IsSearch(msg) && m.screen.Type != ScreenSearch {
+		if ui.IsSearch(msg) && !m.inputFocused() {
 			return m.navigate(SearchScreen(""))
 		}
+
+		// History keys
+		if !m.modalActive() {
+			switch {
+			case ui.IsHistoryBack(msg):
+				if len(m.history) > 0 {
+					return m.goBack()
+				}
+				return m, nil
+			case ui.IsHistoryForward(msg):
+				return m.goForward()
+			case ui.IsBreadcrumbs(msg) && !m.inputFocused():
+				m.crumbCursor = max(len(m.crumbs)-2, 0)
+				return m, nil
+			}
+		}
+		if ui.IsJumpList(msg) && !m.inputFocused() {
+			return m.navigate(JumpsScreen())
+		}
+
+		if ui.IsCycleFit(msg) && !m.modalActive() && !m.inputFocused() {
+			return m.cycleFit()
+		}
+		if ui.IsCycleTheme(msg) && !m.modalActive() && !m.inputFocused() {
+			return m.cycleTheme()
+		}
+		if (ui.IsNextSubgroup(msg) || ui.IsPrevSubgroup(msg)) && !m.modalActive() && !m.inputFocused() {
+			if ui.IsNextSubgroup(msg) {
+				return m.stepSubgroup(1)
+			}
+			return m.stepSubgroup(-1)
+		}
+
+		// Copy keys; only ctrl+y is free while typing
+		if !m.modalActive() && ((ui.IsCopyPartNumber(msg) || ui.IsCopyRow(msg)) && !m.inputFocused() || ui.IsCopySummary(msg)) {
+			if partID := m.selectedPartID(); partID != 0 {
+				return m, copyPart(m.db, partID, msg)
+			}
+		}
+
+		// Split pane layout keys (skipped while typing into an input)
+		if !m.inputFocused() {
+			switch {
+			case ui.IsGrowPane(msg):
+				return m.updateLayout((*ui.SplitLayout).Grow)
+			case ui.IsShrinkPane(msg):
+				return m.updateLayout((*ui.SplitLayout).Shrink)
+			case ui.IsCycleOrientation(msg):
+				return m.updateLayout((*ui.SplitLayout).CycleOrientation)
+			case ui.IsCyclePane(msg):
+				return m.updateLayout((*ui.SplitLayout).CycleHidden)
+			}
+		}
+
+	case tea.MouseMsg:
+		if m.palette != nil {
+			return m.mousePalette(msg)
+		}
+
+		// Clicks on the breadcrumb bar
+		if msg.Y == 0 && ui.IsClick(msg) && !m.modalActive() {
+			if i := m.crumbAt(msg.X); i >= 0 {
+				return m.openCrumb(i)
+			}
+		}
 	}
 
 	// Delegate to active screen
@@ -88,6 +304,14 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.bookmarks, cmd, nav = m.bookmarks.Update(msg)
 	case ScreenNotes:
 		m.notes, cmd, nav = m.notes.Update(msg)
+	case ScreenJumps:
+		m.jumpList, cmd, nav = m.jumpList.Update(msg)
+	case ScreenOrder:
+		m.order, cmd, nav = m.order.Update(msg)
+	case ScreenSheet:
+		m.sheet, cmd, nav = m.sheet.Update(msg)
+	case ScreenStats:
+		m.stats, cmd, nav = m.stats.Update(msg)
 	}
 
 	if nav != nil {
@@ -98,37 +322,70 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) View() string {
-	// Prepend image clear sequence if needed
+	// Prepend image clear and clipboard sequences if needed
 	var clearPrefix string
 	if m.pendingImageClear != 0 {
 		if m.pendingImageClear == 0xFFFFFFFF {
 			clearPrefix = image.ClearAll()
+			if m.sheet != nil {
+				m.sheet.imagesCleared()
+			}
 		} else {
 			clearPrefix = image.Clear(m.pendingImageClear)
 		}
 		m.pendingImageClear = 0
 	}
+	if m.pendingCopy != "" {
+		clearPrefix += m.pendingCopy
+		m.pendingCopy = ""
+	}
+
+	layout := m.layout()
 
 	var content string
 	switch m.screen.Type {
 	case ScreenHome:
-		content = m.home.View(m.width, m.height)
+		content = m.home.View(m.width, m.height, layout)
 	case ScreenGroup:
-		content = m.group.View(m.width, m.height)
+		content = m.group.View(m.width, m.height, layout)
 	case ScreenSubgroup:
-		content = m.subgroup.View(m.width, m.height)
+		content = m.subgroup.View(m.width, m.height, layout)
 	case ScreenPartDetail:
-		content = m.partDetail.View(m.width, m.height)
+		content = m.partDetail.View(m.width, m.height, layout)
 	case ScreenSearch:
-		content = m.search.View(m.width, m.height)
+		content = m.search.View(m.width, m.height, layout)
 	case ScreenBookmarks:
-		content = m.bookmarks.View(m.width, m.height)
+		content = m.bookmarks.View(m.width, m.height, layout)
 	case ScreenNotes:
-		content = m.notes.View(m.width, m.height)
+		content = m.notes.View(m.width, m.height, layout)
+	case ScreenJumps:
+		content = m.jumpList.View(m.width, m.height, layout)
+	case ScreenOrder:
+		content = m.order.View(m.width, m.height, layout)
+	case ScreenSheet:
+		content = m.sheet.View(m.width, m.height, layout)
+	case ScreenStats:
+		content = m.stats.View(m.width, m.height, layout)
 	default:
 		content = "Unknown screen"
 	}
 
+	// Draw the breadcrumb bar and copy confirmation over the blank top row
+	if !m.modalActive() {
+		if first, rest, ok := strings.Cut(content, "\n"); ok && strings.TrimSpace(first) == "" {
+			content = m.renderTopRow() + "\n" + rest
+		}
+	}
+
+	// Draw the command palette over the screen, without the diagram
+	if m.palette != nil {
+		box, x, y := m.palette.render(m.width, m.height)
+		content = ui.Overlay(ui.StripImages(content), box, x, y)
+		if m.screen.Type == ScreenSheet {
+			m.sheet.imagesCleared() // None were sent
+		}
+	}
+
 	// Ensure output fills full terminal height to prevent artifacts
 	content = ui.FitHeight(content, m.height)
 
@@ -136,78 +393,319 @@ func (m *Model) View() string {
 }
 
 func (m *Model) navigate(to Screen) (*Model, tea.Cmd) {
+	m.leave()
+
+	// Push current screen to history; a new path drops the forward stack
+	m.history = append(m.history, m.screen)
+	m.forward = nil
+	m.screen = to
+	m.initScreen(false)
+
+	// Clear screen on navigation to prevent artifacts
+	return m, tea.Batch(tea.ClearScreen, m.screenCmd())
+}
+
+func (m *Model) goBack() (*Model, tea.Cmd) {
+	if len(m.history) == 0 {
+		// Clear all images and quit
+		fmt.Print(image.ClearAll())
+		m.saveLayout()
+		return m, tea.Quit
+	}
+
+	m.leave()
+
+	// Pop from history
+	m.forward = append(m.forward, m.screen)
+	m.screen = m.history[len(m.history)-1]
+	m.history = m.history[:len(m.history)-1]
+	m.initScreen(true)
+
+	// Clear screen on navigation to prevent artifacts
+	return m, tea.Batch(tea.ClearScreen, m.screenCmd())
+}
+
+func (m *Model) goForward() (*Model, tea.Cmd) {
+	if len(m.forward) == 0 {
+		return m, nil
+	}
+
+	m.leave()
+
+	// Pop from the forward stack
+	m.history = append(m.history, m.screen)
+	m.screen = m.forward[len(m.forward)-1]
+	m.forward = m.forward[:len(m.forward)-1]
+	m.initScreen(true)
+
+	// Clear screen on navigation to prevent artifacts
+	return m, tea.Batch(tea.ClearScreen, m.screenCmd())
+}
+
+// leave prepares to move away from the current screen, recording its
+// selection in m.screen so history can restore it
+func (m *Model) leave() {
 	// Mark current image for clearing on next render
 	if imgID := m.getCurrentImageID(); imgID != 0 {
 		m.pendingImageClear = imgID
 	}
 
-	// Push current screen to history
-	m.history = append(m.history, m.screen)
-	m.screen = to
+	switch m.screen.Type {
+	case ScreenHome:
+		m.screen.saveSelection(m.home.menu)
+	case ScreenGroup:
+		m.screen.saveSelection(m.group.menu)
+	case ScreenSubgroup:
+		m.screen.saveSelection(m.subgroup.menu)
+	case ScreenPartDetail:
+		m.screen.Cursor = m.partDetail.cursor
+	case ScreenSearch:
+		// Remember the query and filters so going back restores them
+		m.screen.Query = m.search.input.Value()
+		m.screen.Filter = m.search.filter
+		m.screen.Page = m.search.page
+		m.screen.saveSelection(m.search.menu)
+	case ScreenBookmarks:
+		m.screen.saveSelection(m.bookmarks.menu)
+	case ScreenNotes:
+		m.screen.saveSelection(m.notes.menu)
+	case ScreenJumps:
+		m.screen.saveSelection(m.jumpList.menu)
+	case ScreenOrder:
+		m.screen.saveSelection(m.order.menu)
+	case ScreenSheet:
+		m.screen.Cursor = m.sheet.cursor
+	case ScreenStats:
+		m.screen.saveSelection(m.stats.menu)
+		m.screen.Page = m.stats.section
+	}
+}
 
-	// Initialize new screen model
-	switch to.Type {
+// initScreen builds the model for m.screen. When restore is set (going back
+// or forward) the selection saved by leave is put back.
+func (m *Model) initScreen(restore bool) {
+	s := m.screen
+
+	switch s.Type {
 	case ScreenHome:
 		m.home = NewHomeModel(m.db)
 	case ScreenGroup:
-		m.group = NewGroupModel(m.db, to.GroupID)
+		m.group = NewGroupModel(m.db, s.GroupID, m.dataPath, m.thumbs)
 	case ScreenSubgroup:
-		m.subgroup = NewSubgroupModel(m.db, to.SubgroupID, m.dataPath)
+		if m.subgroup = m.takeSibling(s.SubgroupID); m.subgroup == nil {
+			m.subgroup = NewSubgroupModel(m.db, s.SubgroupID, m.dataPath, m.fit)
+		}
 	case ScreenPartDetail:
-		m.partDetail = NewPartDetailModel(m.db, to.PartID, m.dataPath)
+		m.partDetail = NewPartDetailModel(m.db, s.PartID, m.dataPath, m.config.LinkProviders(), m.fit)
 	case ScreenSearch:
-		m.search = NewSearchModel(m.db, to.Query)
+		m.search = NewSearchModel(m.db, m.dataPath, s.Query, s.Filter, m.fit)
 	case ScreenBookmarks:
-		m.bookmarks = NewBookmarksModel(m.db)
+		m.bookmarks = NewBookmarksModel(m.db, m.dataPath)
 	case ScreenNotes:
 		m.notes = NewNotesModel(m.db)
+	case ScreenJumps:
+		m.jumpList = NewJumpsModel(m.db, m.jumps)
+	case ScreenOrder:
+		m.order = NewOrderModel(m.db, m.dataPath)
+	case ScreenSheet:
+		m.sheet = NewSheetModel(m.db, s.GroupID, m.dataPath, m.thumbs)
+	case ScreenStats:
+		m.stats = NewStatsModel(m.db, m.dataPath, s.Page)
 	}
 
-	// Clear screen on navigation to prevent artifacts
-	return m, tea.ClearScreen
+	if restore {
+		switch s.Type {
+		case ScreenHome:
+			s.restoreSelection(m.home.menu)
+		case ScreenGroup:
+			s.restoreSelection(m.group.menu)
+		case ScreenSubgroup:
+			s.restoreSelection(m.subgroup.menu)
+		case ScreenPartDetail:
+			m.partDetail.cursor = max(min(s.Cursor, m.partDetail.totalItems()-1), 0)
+		case ScreenSearch:
+			if s.Page > 0 && s.Query != "" {
+				m.search.applyResults(m.search.search(s.Query, s.Filter, s.Page))
+			}
+			s.restoreSelection(m.search.menu)
+		case ScreenBookmarks:
+			s.restoreSelection(m.bookmarks.menu)
+		case ScreenNotes:
+			s.restoreSelection(m.notes.menu)
+		case ScreenJumps:
+			s.restoreSelection(m.jumpList.menu)
+		case ScreenOrder:
+			s.restoreSelection(m.order.menu)
+		case ScreenSheet:
+			m.sheet.cursor = max(min(s.Cursor, len(m.sheet.subgroups)-1), 0)
+		case ScreenStats:
+			s.restoreSelection(m.stats.menu)
+		}
+	}
+
+	m.jumps = recordJump(m.jumps, s)
+	m.updateCrumbs()
 }
 
-func (m *Model) goBack() (*Model, tea.Cmd) {
-	if len(m.history) == 0 {
-		// Clear all images and quit
-		fmt.Print(image.ClearAll())
-		return m, tea.Quit
+// layout returns the saved split pane layout for the current screen type
+func (m *Model) layout() ui.SplitLayout {
+	saved, ok := m.config.Layout(m.screen.Type.String())
+	if !ok {
+		return ui.DefaultSplitLayout()
+	}
+	return ui.SplitLayout{
+		Ratio:       saved.Ratio,
+		Orientation: ui.Orientation(saved.Orientation),
+		Hidden:      ui.Pane(saved.Hidden),
 	}
+}
 
-	// Mark current image for clearing on next render
+// layoutSaveMsg saves the layout once the keys changing it pause
+type layoutSaveMsg struct {
+	change int
+}
+
+// updateLayout applies a change to the current screen's layout, saving it
+// once the keys stop or on quit
+func (m *Model) updateLayout(change func(*ui.SplitLayout)) (*Model, tea.Cmd) {
+	layout := m.layout()
+	change(&layout)
+
+	m.config.SetLayout(m.screen.Type.String(), config.Layout{
+		Ratio:       layout.Ratio,
+		Orientation: string(layout.Orientation),
+		Hidden:      string(layout.Hidden),
+	})
+	m.layoutChanges++
+	m.layoutUnsaved = true
+	n := m.layoutChanges
+
+	// The diagram moves or resizes, so clear it before redrawing
 	if imgID := m.getCurrentImageID(); imgID != 0 {
 		m.pendingImageClear = imgID
 	}
 
-	// Pop from history
-	m.screen = m.history[len(m.history)-1]
-	m.history = m.history[:len(m.history)-1]
+	return m, tea.Batch(tea.ClearScreen, tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return layoutSaveMsg{change: n}
+	}))
+}
+
+// saveLayout saves layout changes that haven't been yet
+func (m *Model) saveLayout() {
+	if m.layoutUnsaved {
+		m.layoutUnsaved = false
+		m.saveConfig()
+	}
+}
+
+// saveConfig saves the config, showing any failure as the notice
+func (m *Model) saveConfig() {
+	if err := m.config.Save(); err != nil {
+		m.notice = "Config not saved: " + err.Error()
+	}
+}
+
+// cycleFit switches how parts that don't fit the vehicle are shown, saves
+// the choice and rebuilds the current screen with it
+func (m *Model) cycleFit() (*Model, tea.Cmd) {
+	m.fit.mode = m.fit.mode.next()
+	m.config.Applicability = string(m.fit.mode)
+	clear(m.siblings)
+
+	m.leave()
+	m.initScreen(true)
+	m.notice = m.fit.mode.String()
+	if !m.fit.vehicle.Known() {
+		m.notice += " (set MANUFACTURE_DATE in .env)"
+	}
+	m.saveConfig()
+	return m, tea.ClearScreen
+}
+
+// inputFocused reports whether the current screen is capturing typed text
+func (m *Model) inputFocused() bool {
+	if menu := m.filterMenu(); menu != nil && menu.Filtering() {
+		return true
+	}
+	switch m.screen.Type {
+	case ScreenSearch:
+		return true
+	case ScreenPartDetail:
+		return m.partDetail != nil && (m.partDetail.editor != nil || m.partDetail.attaching)
+	}
+	return false
+}
+
+// screenCmd returns background work the current screen is waiting on, such
+// as loading the selected subgroup's diagram preview or building the
+// subgroups next to this one
+func (m *Model) screenCmd() tea.Cmd {
+	switch m.screen.Type {
+	case ScreenGroup:
+		return m.group.loadThumbnail()
+	case ScreenSheet:
+		return m.sheet.loadVisible()
+	case ScreenSubgroup, ScreenPartDetail:
+		return m.prefetchSiblings()
+	}
+	return nil
+}
 
-	// Re-initialize screen model
+// filterMenu returns the current screen's list, for type-to-filter, or nil
+// if it has none. Search has its own query instead.
+func (m *Model) filterMenu() *ui.Menu {
 	switch m.screen.Type {
 	case ScreenHome:
-		m.home = NewHomeModel(m.db)
+		return m.home.menu
 	case ScreenGroup:
-		m.group = NewGroupModel(m.db, m.screen.GroupID)
+		return m.group.menu
 	case ScreenSubgroup:
-		m.subgroup = NewSubgroupModel(m.db, m.screen.SubgroupID, m.dataPath)
-	case ScreenPartDetail:
-		m.partDetail = NewPartDetailModel(m.db, m.screen.PartID, m.dataPath)
-	case ScreenSearch:
-		m.search = NewSearchModel(m.db, m.screen.Query)
+		return m.subgroup.menu
 	case ScreenBookmarks:
-		m.bookmarks = NewBookmarksModel(m.db)
+		return m.bookmarks.menu
 	case ScreenNotes:
-		m.notes = NewNotesModel(m.db)
+		return m.notes.menu
+	case ScreenJumps:
+		return m.jumpList.menu
+	case ScreenOrder:
+		return m.order.menu
+	case ScreenStats:
+		return m.stats.menu
 	}
+	return nil
+}
 
-	// Clear screen on navigation to prevent artifacts
-	return m, tea.ClearScreen
+// modalActive reports whether the current screen has an open mode
+// (note editor, attach prompt, zoomed diagram, save prompt, picked parts) that esc should close instead of going back
+func (m *Model) modalActive() bool {
+	switch m.screen.Type {
+	case ScreenSearch:
+		return m.search != nil && (m.search.naming || m.search.exporting || m.search.batch.active(m.search.menu))
+	case ScreenGroup:
+		return m.group != nil && m.group.exporting
+	case ScreenSubgroup:
+		return m.subgroup != nil && (m.subgroup.zoom != nil || m.subgroup.exporting || m.subgroup.batch.active(m.subgroup.menu))
+	case ScreenOrder:
+		return m.order != nil && m.order.exporting
+	case ScreenBookmarks:
+		return m.bookmarks != nil && m.bookmarks.batch.active(m.bookmarks.menu)
+	case ScreenPartDetail:
+		return m.partDetail != nil && (m.partDetail.editor != nil || m.partDetail.attaching || m.partDetail.zoom != nil)
+	}
+	return false
 }
 
 // getCurrentImageID returns the image ID from the current screen, if any
 func (m *Model) getCurrentImageID() uint32 {
 	switch m.screen.Type {
+	case ScreenGroup:
+		if m.group != nil {
+			return m.group.ImageID()
+		}
+	case ScreenSheet:
+		if m.sheet != nil {
+			return m.sheet.ImageID()
+		}
 	case ScreenSubgroup:
 		if m.subgroup != nil {
 			return m.subgroup.ImageID()
@@ -0,0 +1,230 @@
+package model
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"delica-tui/db"
+	"delica-tui/ui"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// noteEditor is a full-screen editor for one journal entry, with a body
+// and a tags field; tab moves between them.
+type noteEditor struct {
+	noteID    int // 0 for a new entry
+	title     string
+	body      textarea.Model
+	tags      textinput.Model
+	focusTags bool
+	err       string // Why the last save failed
+}
+
+func newNoteEditor(title string, note *db.Note) *noteEditor {
+	body := textarea.New()
+	body.Placeholder = "Write a note..."
+	body.CharLimit = 10000
+	body.ShowLineNumbers = false
+	body.Prompt = ""
+
+	tags := textinput.New()
+	tags.Prompt = ""
+	tags.Placeholder = "comma separated"
+	tags.CharLimit = 200
+
+	e := &noteEditor{title: title, body: body, tags: tags}
+	if note != nil {
+		e.noteID = note.ID
+		e.body.SetValue(note.Content)
+		e.tags.SetValue(strings.Join(note.Tags, ", "))
+	}
+	return e
+}
+
+// Focus focuses the body and returns the cursor blink command
+func (e *noteEditor) Focus() tea.Cmd {
+	return e.body.Focus()
+}
+
+func (e *noteEditor) Update(msg tea.Msg) tea.Cmd {
+	if msg, ok := msg.(tea.KeyMsg); ok && ui.IsTab(msg) {
+		e.focusTags = !e.focusTags
+		if e.focusTags {
+			e.body.Blur()
+			return e.tags.Focus()
+		}
+		e.tags.Blur()
+		return e.body.Focus()
+	}
+
+	var cmd tea.Cmd
+	if e.focusTags {
+		e.tags, cmd = e.tags.Update(msg)
+	} else {
+		e.body, cmd = e.body.Update(msg)
+	}
+	return cmd
+}
+
+func (e *noteEditor) Content() string {
+	return strings.TrimSpace(e.body.Value())
+}
+
+func (e *noteEditor) Tags() []string {
+	return parseTags(e.tags.Value())
+}
+
+// parseTags splits a tag list on commas and spaces, dropping any leading #
+func parseTags(s string) []string {
+	var tags []string
+	for _, t := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' '
+	}) {
+		if t = strings.TrimLeft(t, "#"); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+func (e *noteEditor) View(width, height int) string {
+	if width == 0 {
+		width = 80
+	}
+	if height == 0 {
+		height = 24
+	}
+
+	e.body.SetWidth(width - 6)
+	bodyHeight := height - splitTop - 9
+	if e.err != "" {
+		bodyHeight--
+	}
+	e.body.SetHeight(max(bodyHeight, 3))
+	e.tags.Width = width - 14
+
+	var b strings.Builder
+
+	// Top margin (2 blank lines to match other pages)
+	b.WriteString("\n\n")
+
+	b.WriteString("  " + ui.HeaderStyle.Render(e.title) + "\n")
+	b.WriteString("  " + ui.DimStyle.Render(strings.Repeat("─", width-6)) + "\n\n")
+
+	for _, line := range strings.Split(e.body.View(), "\n") {
+		b.WriteString("  " + line + "\n")
+	}
+
+	b.WriteString("\n  " + ui.DimStyle.Render("Tags: ") + e.tags.View() + "\n\n")
+	if e.err != "" {
+		b.WriteString("  " + ui.ErrorStyle.Render(e.err) + "\n")
+	}
+	b.WriteString("  " + ui.DimStyle.Render("ctrl+s save (empty to delete)   tab switch field   esc cancel"))
+
+	return b.String()
+}
+
+// noteAuthor returns the name recorded on new notes
+func noteAuthor() string {
+	if author := os.Getenv("NOTE_AUTHOR"); author != "" {
+		return author
+	}
+	return os.Getenv("USER")
+}
+
+// formatTimestamp shows a database timestamp (UTC) in local time
+func formatTimestamp(ts string) string {
+	t, err := time.ParseInLocation(time.DateTime, ts, time.UTC)
+	if err != nil {
+		return ts
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// noteFileEditedMsg reports that the external editor exited
+type noteFileEditedMsg struct {
+	noteID   int // 0 for a new entry
+	path     string
+	original string // File contents before editing
+	err      error
+}
+
+// editNoteExternally writes a note to a temporary Markdown file, with a
+// header of part metadata, and suspends the TUI to open it in $EDITOR.
+func editNoteExternally(part *db.PartWithDiagram, location string, note *db.Note) tea.Cmd {
+	var header strings.Builder
+	header.WriteString("---\n")
+	header.WriteString("part: " + part.PartNumber + "\n")
+	if part.Description != nil {
+		header.WriteString("description: " + *part.Description + "\n")
+	}
+	if part.PNC != nil {
+		header.WriteString("pnc: " + *part.PNC + "\n")
+	}
+	if location != "" {
+		header.WriteString("location: " + location + "\n")
+	}
+	content := ""
+	if note != nil {
+		header.WriteString("created: " + formatTimestamp(note.CreatedAt) + "\n")
+		header.WriteString("tags: " + strings.Join(note.Tags, ", ") + "\n")
+		content = note.Content
+	} else {
+		header.WriteString("tags: \n")
+	}
+	header.WriteString("---\n\n")
+	text := header.String() + content + "\n"
+
+	noteID := 0
+	if note != nil {
+		noteID = note.ID
+	}
+
+	f, err := os.CreateTemp("", "delica-note-*.md")
+	if err == nil {
+		_, err = f.WriteString(text)
+		f.Close()
+	}
+	if err != nil {
+		return func() tea.Msg {
+			return noteFileEditedMsg{noteID: noteID, err: err}
+		}
+	}
+
+	args := append(editorCommand(), f.Name())
+	return tea.ExecProcess(exec.Command(args[0], args[1:]...), func(err error) tea.Msg {
+		return noteFileEditedMsg{noteID: noteID, path: f.Name(), original: text, err: err}
+	})
+}
+
+// editorCommand returns the user's editor command line, from $VISUAL or $EDITOR
+func editorCommand() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"vi"}
+}
+
+// parseNoteFile splits an edited note file into its body and the tags
+// listed in its header. Other header fields are for reference only.
+func parseNoteFile(text string) (content string, tags []string) {
+	body := text
+	if rest, ok := strings.CutPrefix(text, "---\n"); ok {
+		if header, after, found := strings.Cut(rest, "\n---\n"); found {
+			body = after
+			for _, line := range strings.Split(header, "\n") {
+				if value, ok := strings.CutPrefix(line, "tags:"); ok {
+					tags = parseTags(value)
+				}
+			}
+		}
+	}
+	return strings.TrimSpace(body), tags
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseNoteFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		content string
+		tags    []string
+	}{
+		{
+			name:    "header and body",
+			text:    "---\npart: MD123456\ndescription: GASKET\ntags: todo, #engine\n---\n\nReplace at 100k.\n",
+			content: "Replace at 100k.",
+			tags:    []string{"todo", "engine"},
+		},
+		{
+			name:    "empty tags",
+			text:    "---\npart: MD123456\ntags: \n---\n\nBody\n",
+			content: "Body",
+		},
+		{
+			name:    "header edited away",
+			text:    "Just a body\n\nwith lines\n",
+			content: "Just a body\n\nwith lines",
+		},
+		{
+			name:    "unclosed header",
+			text:    "---\ntags: a\nBody",
+			content: "---\ntags: a\nBody",
+		},
+		{
+			name:    "rule in the body",
+			text:    "---\ntags: a b\n---\nAbove\n---\nBelow\n",
+			content: "Above\n---\nBelow",
+			tags:    []string{"a", "b"},
+		},
+		{
+			name:    "emptied",
+			text:    "---\ntags: a\n---\n\n\n",
+			content: "",
+			tags:    []string{"a"},
+		},
+	}
+	for _, tt := range tests {
+		content, tags := parseNoteFile(tt.text)
+		if content != tt.content || !slices.Equal(tags, tt.tags) {
+			t.Errorf("%s: parseNoteFile = %q, %q, want %q, %q", tt.name, content, tags, tt.content, tt.tags)
+		}
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"todo", []string{"todo"}},
+		{"todo, engine", []string{"todo", "engine"}},
+		{"#todo #engine,,", []string{"todo", "engine"}},
+		{"# ,#", nil},
+	}
+	for _, tt := range tests {
+		if got := parseTags(tt.in); !slices.Equal(got, tt.want) {
+			t.Errorf("parseTags(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
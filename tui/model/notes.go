@@ -34,6 +34,9 @@ func NewNotesModel(database *db.DB) *NotesModel {
 		}
 		// Replace newlines with spaces for single-line display
 		hint = strings.ReplaceAll(hint, "\n", " ")
+		if n.Count > 1 {
+			hint = fmt.Sprintf("(%d) %s", n.Count, hint)
+		}
 
 		items = append(items, ui.MenuItem{
 			ID:    fmt.Sprintf("%d", n.PartID),
@@ -59,18 +62,29 @@ func (m *NotesModel) Update(msg tea.Msg) (*NotesModel, tea.Cmd, *Screen) {
 			m.menu.Down()
 		}
 		if ui.IsEnter(msg) {
-			if item := m.menu.Selected(); item != nil {
-				var partID int
-				fmt.Sscanf(item.ID, "%d", &partID)
-				s := PartDetailScreen(partID, false)
-				return m, nil, &s
-			}
+			return m.openSelected()
+		}
+
+	case tea.MouseMsg:
+		if m.menu.Mouse(msg) {
+			return m.openSelected()
 		}
 	}
 	return m, nil, nil
 }
 
-func (m *NotesModel) View(width, height int) string {
+// openSelected navigates to the item under the cursor
+func (m *NotesModel) openSelected() (*NotesModel, tea.Cmd, *Screen) {
+	if item := m.menu.Selected(); item != nil {
+		var partID int
+		fmt.Sscanf(item.ID, "%d", &partID)
+		s := PartDetailScreen(partID, false)
+		return m, nil, &s
+	}
+	return m, nil, nil
+}
+
+func (m *NotesModel) View(width, height int, layout ui.SplitLayout) string {
 	if width == 0 {
 		width = 80
 	}
@@ -92,10 +106,11 @@ func (m *NotesModel) View(width, height int) string {
 		splitHeight = 10
 	}
 
-	leftContent := m.renderLeftPane(splitHeight)
-	rightContent := m.renderRightPane(splitHeight)
+	leftPane, rightPane := layout.Panes(width-2, splitHeight)
+	leftContent := m.renderLeftPane(leftPane.Height)
+	rightContent := m.renderRightPane(rightPane)
 
-	split := ui.RenderSplitPane(leftContent, rightContent, width-2, splitHeight)
+	split := ui.RenderSplitPane(leftContent, rightContent, width-2, splitHeight, layout)
 
 	return header + "\n" + split
 }
@@ -118,7 +133,7 @@ func (m *NotesModel) renderLeftPane(height int) string {
 	return strings.Join(lines, "\n")
 }
 
-func (m *NotesModel) renderRightPane(height int) string {
+func (m *NotesModel) renderRightPane(pane ui.Rect) string {
 	var b strings.Builder
 
 	// Header
@@ -127,7 +142,7 @@ func (m *NotesModel) renderRightPane(height int) string {
 	b.WriteString(ui.DimStyle.Render("─────────────────────────────────"))
 
 	// Adjust menu visible items based on available height (max 15)
-	menuHeight := height - 5
+	menuHeight := pane.Height - 5
 	if menuHeight < 5 {
 		menuHeight = 5
 	}
@@ -151,11 +166,12 @@ func (m *NotesModel) renderRightPane(height int) string {
 		b.WriteString("\n")
 		b.WriteString(ui.DimStyle.Render("press 'n' to add a note"))
 	} else {
+		m.menu.SetOrigin(pane.X, splitTop+pane.Y+strings.Count(b.String(), "\n"), pane.Width)
 		b.WriteString(m.menu.View())
 	}
 
 	b.WriteString("\n\n")
-	b.WriteString(ui.DimStyle.Render("↑↓ navigate   enter select"))
+	b.WriteString(ui.DimStyle.Render("↑↓ navigate   enter select   f filter"))
 
 	return b.String()
 }
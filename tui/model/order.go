@@ -0,0 +1,219 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"delica-tui/bom"
+	"delica-tui/db"
+	"delica-tui/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// OrderModel lists the parts gathered for an order, in the order added.
+type OrderModel struct {
+	db       *db.DB
+	dataPath string
+	items    []db.OrderItem
+	menu     *ui.Menu
+	status   string
+
+	// e was pressed; the next key picks the export format
+	exporting bool
+}
+
+func NewOrderModel(database *db.DB, dataPath string) *OrderModel {
+	items, _ := database.GetOrderItems()
+
+	var menuItems []ui.MenuItem
+	for _, o := range items {
+		label := o.PartNumber
+		if o.PNC != nil {
+			label = fmt.Sprintf("[%s] %s", *o.PNC, o.PartNumber)
+		}
+
+		var hintParts []string
+		if o.Description != nil {
+			hintParts = append(hintParts, *o.Description)
+		}
+		if o.SubgroupName != nil {
+			hintParts = append(hintParts, fmt.Sprintf("%s > %s", o.GroupName, *o.SubgroupName))
+		} else {
+			hintParts = append(hintParts, o.GroupName)
+		}
+
+		menuItems = append(menuItems, ui.MenuItem{
+			ID:    fmt.Sprintf("%d", o.PartID),
+			Label: label,
+			Hint:  strings.Join(hintParts, " - "),
+		})
+	}
+
+	menu := ui.NewMenu(menuItems)
+	menu.LabelWidth = 24
+
+	return &OrderModel{
+		db:       database,
+		dataPath: dataPath,
+		items:    items,
+		menu:     menu,
+	}
+}
+
+func (m *OrderModel) Update(msg tea.Msg) (*OrderModel, tea.Cmd, *Screen) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		m.status = ""
+		if m.exporting {
+			m.exporting = false
+			m.status = exportBOM(m.bom, m.dataPath, msg)
+			return m, nil, nil
+		}
+		if ui.IsUp(msg) {
+			m.menu.Up()
+		}
+		if ui.IsDown(msg) {
+			m.menu.Down()
+		}
+		if ui.IsEnter(msg) {
+			return m.openSelected()
+		}
+		if ui.IsDelete(msg) {
+			m.removeSelected()
+		}
+		if ui.IsExport(msg) && len(m.items) > 0 {
+			m.exporting = true
+		}
+
+	case tea.MouseMsg:
+		if m.menu.Mouse(msg) {
+			return m.openSelected()
+		}
+	}
+	return m, nil, nil
+}
+
+// openSelected navigates to the item under the cursor
+func (m *OrderModel) openSelected() (*OrderModel, tea.Cmd, *Screen) {
+	if m.menu.Selected() == nil {
+		return m, nil, nil
+	}
+	s := PartDetailScreen(m.items[m.menu.Cursor].PartID, false)
+	return m, nil, &s
+}
+
+// removeSelected takes the part under the cursor off the list
+func (m *OrderModel) removeSelected() {
+	if m.menu.Selected() == nil || m.db.RemoveFromOrder(m.items[m.menu.Cursor].PartID) != nil {
+		return
+	}
+	cursor := m.menu.Cursor
+	*m = *NewOrderModel(m.db, m.dataPath)
+	m.menu.SetCursor(cursor)
+}
+
+// bom builds a bill of materials from the whole list
+func (m *OrderModel) bom() (*bom.BOM, error) {
+	var ids []int
+	for _, o := range m.items {
+		ids = append(ids, o.PartID)
+	}
+	return bom.FromParts(m.db, m.dataPath, "Order List", loadParts(m.db, ids)), nil
+}
+
+func (m *OrderModel) View(width, height int, layout ui.SplitLayout) string {
+	if width == 0 {
+		width = 80
+	}
+	if height == 0 {
+		height = 24
+	}
+
+	// Header
+	headerStyle := lipgloss.NewStyle().
+		Width(width - 2).
+		Padding(1, 1, 0, 1).
+		Align(lipgloss.Right)
+
+	header := headerStyle.Render(ui.DimStyle.Render("esc back"))
+
+	// Split pane content
+	splitHeight := height - 5
+	if splitHeight < 10 {
+		splitHeight = 10
+	}
+
+	leftPane, rightPane := layout.Panes(width-2, splitHeight)
+	leftContent := m.renderLeftPane(leftPane.Height)
+	rightContent := m.renderRightPane(rightPane)
+
+	split := ui.RenderSplitPane(leftContent, rightContent, width-2, splitHeight, layout)
+
+	return header + "\n" + split
+}
+
+func (m *OrderModel) renderLeftPane(height int) string {
+	var lines []string
+
+	lines = append(lines, ui.HeaderStyle.Render("ORDER LIST"))
+	lines = append(lines, "")
+	lines = append(lines, countParts(len(m.items)))
+	lines = append(lines, "")
+	lines = append(lines, ui.DimStyle.Render("Pick parts in a list"))
+	lines = append(lines, ui.DimStyle.Render("and press ctrl+x o"))
+
+	// Pad to fill height
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m *OrderModel) renderRightPane(pane ui.Rect) string {
+	var b strings.Builder
+
+	// Header
+	b.WriteString(ui.HeaderStyle.Render("PARTS TO ORDER"))
+	b.WriteString("\n")
+	b.WriteString(ui.DimStyle.Render("─────────────────────────────────"))
+
+	// Adjust menu visible items based on available height (max 15)
+	menuHeight := pane.Height - 5
+	if menuHeight < 5 {
+		menuHeight = 5
+	}
+	if menuHeight > 15 {
+		menuHeight = 15
+	}
+	m.menu.MaxVisibleItems = menuHeight
+
+	// One less blank line if menu scrolls (to account for scroll indicator)
+	if len(m.menu.Items) > m.menu.MaxVisibleItems {
+		b.WriteString("\n")
+	} else {
+		b.WriteString("\n\n")
+	}
+
+	// Menu
+	if len(m.items) == 0 {
+		b.WriteString(ui.DimStyle.Render("The order list is empty"))
+	} else {
+		m.menu.SetOrigin(pane.X, splitTop+pane.Y+strings.Count(b.String(), "\n"), pane.Width)
+		b.WriteString(m.menu.View())
+	}
+
+	help := "↑↓ navigate   enter select   f filter   x remove   e export"
+	switch {
+	case m.exporting:
+		help = exportHelp
+	case m.status != "":
+		help = m.status
+	}
+	b.WriteString("\n\n")
+	b.WriteString(ui.DimStyle.Render(help))
+
+	return b.String()
+}
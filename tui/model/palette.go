@@ -0,0 +1,280 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"delica-tui/db"
+	"delica-tui/ui"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// paletteEntry is one thing the command palette can open or do
+type paletteEntry struct {
+	label  string // What the query is matched against
+	kind   string // Shown as the hint, e.g. "subgroup" or "action"
+	screen *Screen
+	action func(m *Model) (*Model, tea.Cmd)
+}
+
+// paletteModel is the ctrl+p overlay: a query and the entries matching it,
+// best first
+type paletteModel struct {
+	input   textinput.Model
+	entries []paletteEntry
+	shown   []int // Indexes into entries, in menu order
+	menu    *ui.Menu
+}
+
+// openPalette gathers what the palette offers from the current screen and
+// the catalog. Diagrams are cleared, since they would draw over it.
+func (m *Model) openPalette() (*Model, tea.Cmd) {
+	ti := textinput.New()
+	ti.Prompt = "> "
+	ti.Placeholder = "Go to a group, subgroup, bookmark or saved search, or run an action..."
+	ti.CharLimit = 100
+	ti.Focus()
+
+	p := &paletteModel{input: ti, menu: ui.NewMenu(nil)}
+	p.entries = append(m.paletteActions(), paletteTargets(m.db)...)
+	p.filter()
+	m.palette = p
+
+	if imgID := m.getCurrentImageID(); imgID != 0 {
+		m.pendingImageClear = imgID
+	}
+	return m, textinput.Blink
+}
+
+// paletteActions lists the actions that apply on the current screen
+func (m *Model) paletteActions() []paletteEntry {
+	var entries []paletteEntry
+	action := func(label string, run func(m *Model) (*Model, tea.Cmd)) {
+		entries = append(entries, paletteEntry{label: label, kind: "action", action: run})
+	}
+	screen := func(label string, s Screen) {
+		entries = append(entries, paletteEntry{label: label, kind: "screen", screen: &s})
+	}
+
+	if partID := m.selectedPartID(); partID != 0 {
+		action("Toggle bookmark", func(m *Model) (*Model, tea.Cmd) {
+			return m.toggleBookmark(partID)
+		})
+	}
+	if m.screen.Type == ScreenGroup {
+		screen("Contact sheet of this group's diagrams", SheetScreen(m.screen.GroupID))
+	}
+	if m.canExport() {
+		action("Export bill of materials", func(m *Model) (*Model, tea.Cmd) {
+			m.startExport()
+			return m, nil
+		})
+	}
+	for _, name := range themeNames(m.config) {
+		action("Theme: "+name, func(m *Model) (*Model, tea.Cmd) {
+			return m.setTheme(name)
+		})
+	}
+	action("Dim, hide or show parts that don't fit", (*Model).cycleFit)
+
+	screen("Home", HomeScreen())
+	screen("Search", SearchScreen(""))
+	screen("Bookmarks", BookmarksScreen())
+	screen("Notes", NotesScreen())
+	screen("Order List", OrderScreen())
+	screen("Recently visited", JumpsScreen())
+	screen("Catalog statistics and scrape progress", StatsScreen())
+	return entries
+}
+
+// paletteTargets lists every group and subgroup, bookmarked part and saved
+// search. Those that can't be read are left out.
+func paletteTargets(database *db.DB) []paletteEntry {
+	var entries []paletteEntry
+	add := func(label, kind string, s Screen) {
+		entries = append(entries, paletteEntry{label: label, kind: kind, screen: &s})
+	}
+
+	groups, _ := database.GetGroups()
+	for _, g := range groups {
+		add(g.Name, "group", GroupScreen(g.ID))
+	}
+	for _, g := range groups {
+		subgroups, _ := database.GetSubgroups(g.ID)
+		for _, s := range subgroups {
+			add(g.Name+" › "+s.Name, "subgroup", SubgroupScreen(s.ID))
+		}
+	}
+
+	bookmarks, _ := database.GetBookmarks()
+	for _, b := range bookmarks {
+		label := b.PartNumber
+		if b.Description != nil {
+			label += " " + *b.Description
+		}
+		add(label, "bookmark", PartDetailScreen(b.PartID, false))
+	}
+
+	saved, _ := database.GetSavedSearches()
+	for _, s := range saved {
+		add("/ "+s.Name, "saved search", FilteredSearchScreen(s.Query, s.Filter))
+	}
+	return entries
+}
+
+// updatePalette handles keys while the palette is open
+func (m *Model) updatePalette(msg tea.KeyMsg) (*Model, tea.Cmd) {
+	p := m.palette
+	switch {
+	case ui.IsBack(msg), ui.IsPalette(msg):
+		m.palette = nil
+		return m, nil
+	case msg.Type == tea.KeyUp:
+		p.menu.Up()
+		return m, nil
+	case msg.Type == tea.KeyDown:
+		p.menu.Down()
+		return m, nil
+	case ui.IsEnter(msg):
+		return m.runPaletteEntry()
+	}
+
+	query := p.input.Value()
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	if p.input.Value() != query {
+		p.filter()
+	}
+	return m, cmd
+}
+
+// mousePalette handles the mouse while the palette is open; a click on an
+// entry runs it
+func (m *Model) mousePalette(msg tea.MouseMsg) (*Model, tea.Cmd) {
+	if m.palette.menu.Mouse(msg) {
+		return m.runPaletteEntry()
+	}
+	return m, nil
+}
+
+// runPaletteEntry closes the palette and opens or runs the selected entry
+func (m *Model) runPaletteEntry() (*Model, tea.Cmd) {
+	p := m.palette
+	m.palette = nil
+	if len(p.shown) == 0 {
+		return m, nil
+	}
+	e := p.entries[p.shown[p.menu.Cursor]]
+	if e.action != nil {
+		return e.action(m)
+	}
+	return m.navigate(*e.screen)
+}
+
+// toggleBookmark bookmarks a part, or removes its bookmark
+func (m *Model) toggleBookmark(partID int) (*Model, tea.Cmd) {
+	bookmarked, err := m.db.IsBookmarked(partID)
+	if err != nil {
+		return m, nil
+	}
+	if bookmarked {
+		err = m.db.RemoveBookmark(partID)
+		m.notice = "Bookmark removed"
+	} else {
+		err = m.db.AddBookmark(partID)
+		m.notice = "Bookmarked"
+	}
+	if err != nil {
+		m.notice = ""
+		return m, nil
+	}
+
+	// The screen may show bookmarks, so build it again
+	m.leave()
+	m.initScreen(true)
+	return m, nil
+}
+
+// canExport reports whether the current screen has a bill of materials to
+// export
+func (m *Model) canExport() bool {
+	switch m.screen.Type {
+	case ScreenGroup:
+		return len(m.group.subgroups) > 0
+	case ScreenSubgroup:
+		return len(m.subgroup.parts) > 0
+	case ScreenOrder:
+		return len(m.order.items) > 0
+	}
+	return false
+}
+
+// startExport asks the current screen for an export format, as e does
+func (m *Model) startExport() {
+	switch m.screen.Type {
+	case ScreenGroup:
+		m.group.exporting = true
+	case ScreenSubgroup:
+		m.subgroup.exporting = true
+	case ScreenOrder:
+		m.order.exporting = true
+	}
+}
+
+// filter matches the entries against the query, best first. Ties keep the
+// order the entries were gathered in.
+func (p *paletteModel) filter() {
+	query := p.input.Value()
+	scores := make(map[int]int)
+	p.shown = p.shown[:0]
+	for i, e := range p.entries {
+		if score, _, ok := ui.FuzzyMatch(query, e.label); ok {
+			scores[i] = score
+			p.shown = append(p.shown, i)
+		}
+	}
+	slices.SortStableFunc(p.shown, func(a, b int) int {
+		return scores[b] - scores[a]
+	})
+
+	items := make([]ui.MenuItem, len(p.shown))
+	for n, i := range p.shown {
+		items[n] = ui.MenuItem{ID: fmt.Sprint(i), Label: p.entries[i].label, Hint: p.entries[i].kind}
+	}
+	p.menu.Items = items
+	p.menu.Cursor = 0
+}
+
+// render draws the palette box, recording where its menu lands
+func (p *paletteModel) render(width, height int) (string, int, int) {
+	boxWidth := min(80, width-4)
+	p.menu.MaxVisibleItems = max(min(12, height-10), 3)
+	p.input.Width = boxWidth - 6
+
+	var b strings.Builder
+	b.WriteString(p.input.View() + "\n\n")
+	if len(p.shown) == 0 {
+		b.WriteString(ui.DimStyle.Render("Nothing matches") + "\n")
+	} else {
+		// Hints line up in a column; long labels are cut to make room
+		p.menu.LabelWidth = boxWidth - 22
+		for n, i := range p.shown {
+			p.menu.Items[n].Label = ansi.Truncate(p.entries[i].label, p.menu.LabelWidth, "…")
+		}
+		b.WriteString(p.menu.View() + "\n")
+	}
+	b.WriteString("\n" + ui.DimStyle.Render("↑↓ choose   enter open   esc close"))
+
+	box := ui.BoxStyle.Width(boxWidth - 2).Render(b.String())
+	x := max((width-lipgloss.Width(box))/2, 0)
+	y := 3
+
+	// The menu starts below the border, the input and a blank line
+	p.menu.SetOrigin(x+2, y+3, boxWidth-4)
+	return box, x, y
+}
@@ -8,11 +8,12 @@ import (
 	"runtime"
 	"strings"
 
+	"delica-tui/config"
 	"delica-tui/db"
 	"delica-tui/image"
 	"delica-tui/ui"
 
-	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -26,18 +27,40 @@ type PartDetailModel struct {
 	subgroup   *db.Subgroup
 	isBookmark bool
 	img        *image.KittyImage
+	imgPath    string
 	imgError   string
+	imgRect    ui.Rect // Screen area covered by the diagram, for click-to-zoom
+	zoom       *zoomView
 	subgroups  []db.SubgroupWithGroup
-	links      []string // URLs for external links
-	cursor     int      // unified cursor for subgroups + links
-
-	// Note editing
-	note        *string
-	editingNote bool
-	noteInput   textarea.Model
+	unfit      []string // Why the part doesn't fit the vehicle in each subgroup ("" if it does)
+	hiddenSubs int      // Subgroups left out because the part doesn't fit there
+	fitReason  string   // Why this entry doesn't fit the vehicle ("" if it does)
+	fit        fit
+	links      []partLink // External links, from the configured providers
+	cursor     int        // unified cursor for notes + attachments + subgroups + links
+	dataPath   string
+
+	// Hit-testing for mouse clicks, recorded by the last render
+	infoPane ui.Rect
+	itemRows []int // Row within the info pane of each cursor item
+
+	// Notes journal
+	notes         []db.Note
+	editor        *noteEditor
+	confirmDelete string // Item x was pressed on once, e.g. "note:12"
+	status        string
+
+	// Attachments, and the prompt for a file to attach
+	attachments []db.Attachment
+	attaching   bool
+	attachInput textinput.Model
+
+	// Notes rendered as Markdown, by note ID, for the current pane width
+	renderedNotes map[int]string
+	renderedWidth int
 }
 
-func NewPartDetailModel(database *db.DB, partID int, dataPath string) *PartDetailModel {
+func NewPartDetailModel(database *db.DB, partID int, dataPath string, providers []config.LinkProvider, fit fit) *PartDetailModel {
 	part, _ := database.GetPart(partID)
 	var diagram *db.Diagram
 	var group *db.Group
@@ -52,16 +75,17 @@ func NewPartDetailModel(database *db.DB, partID int, dataPath string) *PartDetai
 	}
 
 	isBookmark, _ := database.IsBookmarked(partID)
-	note, _ := database.GetNote(partID)
+	notes, _ := database.GetPartNotes(partID)
+
+	var attachments []db.Attachment
+	if part != nil {
+		attachments, _ = database.GetAttachments(part.PartNumber)
+	}
 
-	// Initialize textarea for note editing
-	ti := textarea.New()
-	ti.Placeholder = "Add a note..."
-	ti.SetWidth(40)
-	ti.SetHeight(3)
-	ti.CharLimit = 500
-	ti.ShowLineNumbers = false
-	ti.Prompt = ""
+	ai := textinput.New()
+	ai.Prompt = ""
+	ai.Placeholder = "Path to a photo or file..."
+	ai.CharLimit = 500
 
 	// Get all subgroups containing this part number
 	var subgroups []db.SubgroupWithGroup
@@ -69,40 +93,31 @@ func NewPartDetailModel(database *db.DB, partID int, dataPath string) *PartDetai
 		subgroups, _ = database.GetSubgroupsForPartNumber(part.PartNumber)
 	}
 
-	// Build links list
-	var links []string
+	// Check each subgroup against the vehicle
+	var fitReason string
+	var unfit []string
+	hiddenSubs := 0
 	if part != nil {
-		subgroupID := ""
-		if part.SubgroupID != nil {
-			subgroupID = *part.SubgroupID
-		}
-		detailPageID := ""
-		if part.DetailPageID != nil {
-			detailPageID = *part.DetailPageID
-		}
-		frameName := os.Getenv("FRAME_NAME")
-		if frameName == "" {
-			frameName = "pd6w"
-		}
-		trimCode := os.Getenv("TRIM_CODE")
-		if trimCode == "" {
-			trimCode = "hseue9"
-		}
-		frameNo := os.Getenv("FRAME_NO")
-		if frameNo == "" {
-			frameNo = "PD6W-0500900"
-		}
-		epcURL := fmt.Sprintf("https://mitsubishi.epc-data.com/delica_space_gear/%s/%s/%s/%s/?frame_no=%s",
-			frameName, trimCode, subgroupID, detailPageID, frameNo)
-
-		partNum := part.PartNumber
-		if part.ReplacementPartNumber != nil {
-			partNum = *part.ReplacementPartNumber
+		_, fitReason = fit.check(part.Part)
+
+		entries, _ := database.GetPartsForPartNumber(part.PartNumber)
+		var kept []db.SubgroupWithGroup
+		for _, sg := range subgroups {
+			reason := subgroupFit(fit, entries, sg.SubgroupID)
+			if reason != "" && fit.hides() {
+				hiddenSubs++
+				continue
+			}
+			kept = append(kept, sg)
+			unfit = append(unfit, reason)
 		}
-		amayamaURL := fmt.Sprintf("https://www.amayama.com/en/part/mitsubishi/%s", partNum)
-		amazonURL := fmt.Sprintf("https://www.amazon.com/s?k=%s", partNum)
+		subgroups = kept
+	}
 
-		links = []string{epcURL, amayamaURL, amazonURL}
+	// Build links list
+	var links []partLink
+	if part != nil {
+		links = partLinks(providers, part)
 	}
 
 	m := &PartDetailModel{
@@ -114,16 +129,23 @@ func NewPartDetailModel(database *db.DB, partID int, dataPath string) *PartDetai
 		subgroup:   subgroup,
 		isBookmark: isBookmark,
 		subgroups:  subgroups,
+		unfit:      unfit,
+		hiddenSubs: hiddenSubs,
+		fitReason:  fitReason,
+		fit:        fit,
 		links:      links,
-		cursor:     0,
-		note:        note,
-		editingNote: false,
-		noteInput:   ti,
+		cursor:     len(notes) + len(attachments), // Start on the first subgroup
+		dataPath:   dataPath,
+		notes:      notes,
+
+		attachments: attachments,
+		attachInput: ai,
 	}
 
 	// Load image - use larger size for better visibility
 	if part != nil && part.ImagePath != nil {
 		imgPath := filepath.Join(dataPath, *part.ImagePath)
+		m.imgPath = imgPath
 		if img, err := image.LoadAndScale(imgPath, 92, 46); err == nil {
 			m.img = img
 		} else {
@@ -134,16 +156,60 @@ func NewPartDetailModel(database *db.DB, partID int, dataPath string) *PartDetai
 	return m
 }
 
+// subgroupFit returns why none of a part number's entries in a subgroup
+// fit the vehicle, or "" if one does
+func subgroupFit(fit fit, entries []db.PartWithDiagram, subgroupID string) string {
+	reason := ""
+	for _, e := range entries {
+		if e.SubgroupID == nil || *e.SubgroupID != subgroupID {
+			continue
+		}
+		ok, why := fit.check(e.Part)
+		if ok {
+			return ""
+		}
+		reason = why
+	}
+	return reason
+}
+
 func (m *PartDetailModel) totalItems() int {
-	return len(m.subgroups) + len(m.links)
+	return m.linkOffset() + len(m.links)
+}
+
+// Cursor positions where each section of items starts
+func (m *PartDetailModel) attachmentOffset() int { return len(m.notes) }
+func (m *PartDetailModel) subgroupOffset() int   { return m.attachmentOffset() + len(m.attachments) }
+func (m *PartDetailModel) linkOffset() int       { return m.subgroupOffset() + len(m.subgroups) }
+
+// selectedNote returns the journal entry under the cursor, if any
+func (m *PartDetailModel) selectedNote() *db.Note {
+	if m.cursor < len(m.notes) {
+		return &m.notes[m.cursor]
+	}
+	return nil
+}
+
+// selectedAttachment returns the attachment under the cursor, if any
+func (m *PartDetailModel) selectedAttachment() *db.Attachment {
+	i := m.cursor - m.attachmentOffset()
+	if i >= 0 && i < len(m.attachments) {
+		return &m.attachments[i]
+	}
+	return nil
+}
+
+func (m *PartDetailModel) selectedSubgroupIndex() int {
+	return m.cursor - m.subgroupOffset()
 }
 
 func (m *PartDetailModel) isSubgroupSelected() bool {
-	return m.cursor < len(m.subgroups)
+	i := m.selectedSubgroupIndex()
+	return i >= 0 && i < len(m.subgroups)
 }
 
 func (m *PartDetailModel) selectedLinkIndex() int {
-	return m.cursor - len(m.subgroups)
+	return m.cursor - m.linkOffset()
 }
 
 func openURL(url string) error {
@@ -162,38 +228,42 @@ func openURL(url string) error {
 }
 
 func (m *PartDetailModel) Update(msg tea.Msg) (*PartDetailModel, tea.Cmd, *Screen) {
-	// Handle note editing mode
-	if m.editingNote {
-		switch msg := msg.(type) {
-		case tea.KeyMsg:
-			if ui.IsSaveNote(msg) {
-				// Save or delete note
-				content := strings.TrimSpace(m.noteInput.Value())
-				if content == "" {
-					m.db.RemoveNote(m.partID)
-					m.note = nil
-				} else {
-					m.db.SetNote(m.partID, content)
-					m.note = &content
-				}
-				m.editingNote = false
-				return m, nil, nil
-			}
-			if ui.IsBack(msg) {
-				// Cancel editing
-				m.editingNote = false
-				return m, nil, nil
-			}
-		}
-		// Pass all other keys to textarea
-		var cmd tea.Cmd
-		m.noteInput, cmd = m.noteInput.Update(msg)
-		return m, cmd, nil
+	if m.editor != nil {
+		return m.updateEditor(msg)
+	}
+	if m.attaching {
+		return m.updateAttach(msg)
+	}
+
+	if m.zoom != nil {
+		return m.updateZoom(msg)
 	}
 
 	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		return m.updateMouse(msg)
+
+	case noteFileEditedMsg:
+		m.finishExternalEdit(msg)
+		return m, tea.ClearScreen, nil
+
 	case tea.KeyMsg:
 		totalItems := m.totalItems()
+		m.status = ""
+
+		// Deleting a note or attachment takes a second x to confirm
+		pendingDelete := m.confirmDelete
+		m.confirmDelete = ""
+		if ui.IsDelete(msg) {
+			m.deleteSelected(pendingDelete)
+			return m, nil, nil
+		}
+
+		if ui.IsAttach(msg) && m.part != nil {
+			m.attaching = true
+			m.attachInput.SetValue("")
+			return m, m.attachInput.Focus(), nil
+		}
 
 		if totalItems > 0 {
 			if ui.IsUp(msg) {
@@ -209,22 +279,14 @@ func (m *PartDetailModel) Update(msg tea.Msg) (*PartDetailModel, tea.Cmd, *Scree
 				return m, nil, nil
 			}
 			if ui.IsEnter(msg) {
-				if m.isSubgroupSelected() {
-					// Navigate to subgroup
-					selected := m.subgroups[m.cursor]
-					s := SubgroupScreen(selected.SubgroupID)
-					return m, nil, &s
-				} else {
-					// Open link in browser
-					linkIdx := m.selectedLinkIndex()
-					if linkIdx >= 0 && linkIdx < len(m.links) {
-						openURL(m.links[linkIdx])
-					}
-				}
-				return m, nil, nil
+				return m.openSelected()
 			}
 		}
 
+		if ui.IsZoom(msg) {
+			return m.openZoom()
+		}
+
 		if ui.IsBookmark(msg) {
 			if m.isBookmark {
 				m.db.RemoveBookmark(m.partID)
@@ -236,21 +298,294 @@ func (m *PartDetailModel) Update(msg tea.Msg) (*PartDetailModel, tea.Cmd, *Scree
 		}
 
 		if ui.IsNote(msg) {
-			// Enter note editing mode
-			m.editingNote = true
-			if m.note != nil {
-				m.noteInput.SetValue(*m.note)
-			} else {
-				m.noteInput.SetValue("")
+			return m.openEditor(nil)
+		}
+
+		if ui.IsExternalEdit(msg) && m.part != nil {
+			location := ""
+			if m.group != nil && m.subgroup != nil {
+				location = m.group.Name + " > " + m.subgroup.Name
+			}
+			return m, editNoteExternally(m.part, location, m.selectedNote()), nil
+		}
+	}
+	return m, nil, nil
+}
+
+// finishExternalEdit saves a note file after $EDITOR exits. The note is
+// left alone if the file wasn't changed, and the file is kept if the note
+// can't be saved.
+func (m *PartDetailModel) finishExternalEdit(msg noteFileEditedMsg) {
+	if msg.err != nil {
+		if msg.path != "" {
+			os.Remove(msg.path)
+		}
+		m.status = "Editor failed: " + msg.err.Error()
+		return
+	}
+
+	data, err := os.ReadFile(msg.path)
+	if err != nil {
+		m.status = "Could not read note: " + err.Error()
+		return
+	}
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	if text != msg.original {
+		content, tags := parseNoteFile(text)
+		if err := m.saveNote(msg.noteID, content, tags); err != nil {
+			m.status += " (kept in " + msg.path + ")"
+			return
+		}
+	}
+	os.Remove(msg.path)
+}
+
+// saveNote adds, updates or (when emptied) deletes a journal entry. A
+// failure is shown in the status line.
+func (m *PartDetailModel) saveNote(noteID int, content string, tags []string) error {
+	var err error
+	switch {
+	case noteID == 0 && content != "":
+		_, err = m.db.AddNote(m.partID, content, noteAuthor(), tags)
+	case noteID != 0 && content == "":
+		if err = m.db.RemoveNote(noteID); err == nil {
+			m.status = "Note deleted"
+		}
+	case noteID != 0:
+		err = m.db.UpdateNote(noteID, content, tags)
+	}
+	if err != nil {
+		m.status = "Could not save note: " + err.Error()
+		return err
+	}
+	m.reloadItems()
+	return nil
+}
+
+// deleteSelected deletes the note or attachment under the cursor if x was
+// already pressed on it, and otherwise asks for confirmation
+func (m *PartDetailModel) deleteSelected(pending string) {
+	var key string
+	var remove func() error
+	if note := m.selectedNote(); note != nil {
+		key = fmt.Sprintf("note:%d", note.ID)
+		remove = func() error { return m.db.RemoveNote(note.ID) }
+	} else if a := m.selectedAttachment(); a != nil {
+		key = fmt.Sprintf("attachment:%d", a.ID)
+		remove = func() error {
+			if err := m.db.RemoveAttachment(a.ID); err != nil {
+				return err
+			}
+			os.Remove(filepath.Join(m.dataPath, a.Path))
+			return nil
+		}
+	} else {
+		return
+	}
+
+	if pending != key {
+		m.confirmDelete = key
+		return
+	}
+	if err := remove(); err != nil {
+		m.status = "Could not delete: " + err.Error()
+		return
+	}
+	m.reloadItems()
+}
+
+// updateAttach handles input while the attach prompt is open
+func (m *PartDetailModel) updateAttach(msg tea.Msg) (*PartDetailModel, tea.Cmd, *Screen) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case ui.IsBack(msg):
+			m.attaching = false
+			m.attachInput.Blur()
+			return m, nil, nil
+		case ui.IsEnter(msg):
+			path := cleanInputPath(m.attachInput.Value())
+			if path == "" {
+				return m, nil, nil
+			}
+			m.attaching = false
+			m.attachInput.Blur()
+
+			name, rel, size, err := storeAttachment(m.dataPath, m.part.PartNumber, path)
+			if err == nil {
+				err = m.db.AddAttachment(m.part.PartNumber, name, rel, size)
+			}
+			if err != nil {
+				m.status = "Attach failed: " + err.Error()
+				return m, nil, nil
+			}
+			m.reloadItems()
+			m.cursor = m.subgroupOffset() - 1 // The new attachment
+			return m, nil, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.attachInput, cmd = m.attachInput.Update(msg)
+	return m, cmd, nil
+}
+
+// openEditor opens the full-screen editor for a journal entry,
+// or for a new entry if note is nil
+func (m *PartDetailModel) openEditor(note *db.Note) (*PartDetailModel, tea.Cmd, *Screen) {
+	if m.part == nil {
+		return m, nil, nil
+	}
+	title := strings.ToUpper(m.part.PartNumber) + " - NEW NOTE"
+	if note != nil {
+		title = strings.ToUpper(m.part.PartNumber) + " - NOTE " + formatTimestamp(note.CreatedAt)
+	}
+	m.editor = newNoteEditor(title, note)
+
+	cmds := []tea.Cmd{m.editor.Focus(), tea.ClearScreen}
+	if m.img != nil {
+		cmds = append(cmds, clearImage(m.img.ID()))
+	}
+	return m, tea.Batch(cmds...), nil
+}
+
+// updateEditor handles input while the note editor is open. Saving an
+// existing entry with no content deletes it. If saving fails the editor
+// stays open so the text isn't lost.
+func (m *PartDetailModel) updateEditor(msg tea.Msg) (*PartDetailModel, tea.Cmd, *Screen) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case ui.IsSaveNote(msg):
+			if err := m.saveNote(m.editor.noteID, m.editor.Content(), m.editor.Tags()); err != nil {
+				m.editor.err = m.status
+				m.status = ""
+				return m, nil, nil
+			}
+			m.editor = nil
+			return m, tea.ClearScreen, nil
+		case ui.IsBack(msg):
+			m.editor = nil
+			return m, tea.ClearScreen, nil
+		}
+	}
+	return m, m.editor.Update(msg), nil
+}
+
+// reloadItems re-reads the notes journal and attachments, keeping the
+// cursor on the same subgroup or link if it was on one
+func (m *PartDetailModel) reloadItems() {
+	offset := m.cursor - m.subgroupOffset()
+	m.notes, _ = m.db.GetPartNotes(m.partID)
+	m.renderedNotes = nil
+	if m.part != nil {
+		m.attachments, _ = m.db.GetAttachments(m.part.PartNumber)
+	}
+	if offset >= 0 {
+		m.cursor = m.subgroupOffset() + offset
+	}
+	m.cursor = max(min(m.cursor, m.totalItems()-1), 0)
+}
+
+// openSelected edits the note, shows the attachment, navigates to the
+// subgroup or opens the link under the cursor
+func (m *PartDetailModel) openSelected() (*PartDetailModel, tea.Cmd, *Screen) {
+	if note := m.selectedNote(); note != nil {
+		return m.openEditor(note)
+	}
+
+	if a := m.selectedAttachment(); a != nil {
+		path := filepath.Join(m.dataPath, a.Path)
+		if !image.IsImage(path) {
+			// Other files open in the system viewer
+			if err := openURL(path); err != nil {
+				m.status = "Could not open " + a.Name + ": " + err.Error()
+			}
+			return m, nil, nil
+		}
+		var cmds []tea.Cmd
+		if m.img != nil {
+			cmds = append(cmds, clearImage(m.img.ID()))
+		}
+		m.zoom = newZoomView(path, a.Name)
+		return m, tea.Batch(append(cmds, tea.ClearScreen)...), nil
+	}
+
+	if m.isSubgroupSelected() {
+		// Navigate to subgroup
+		selected := m.subgroups[m.selectedSubgroupIndex()]
+		s := SubgroupScreen(selected.SubgroupID)
+		return m, nil, &s
+	}
+
+	// Open link in browser
+	linkIdx := m.selectedLinkIndex()
+	if linkIdx >= 0 && linkIdx < len(m.links) {
+		openURL(m.links[linkIdx].url)
+	}
+	return m, nil, nil
+}
+
+func (m *PartDetailModel) updateMouse(msg tea.MouseMsg) (*PartDetailModel, tea.Cmd, *Screen) {
+	switch {
+	case ui.IsWheelUp(msg):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case ui.IsWheelDown(msg):
+		if m.cursor < m.totalItems()-1 {
+			m.cursor++
+		}
+	case ui.IsClick(msg):
+		if m.img != nil && m.imgRect.Contains(msg.X, msg.Y) {
+			return m.openZoom()
+		}
+		if !m.infoPane.Contains(msg.X, msg.Y-splitTop) {
+			return m, nil, nil
+		}
+		row := msg.Y - splitTop - m.infoPane.Y
+		for i, itemRow := range m.itemRows {
+			if itemRow == row {
+				m.cursor = i
+				return m.openSelected()
 			}
-			m.noteInput.Focus()
-			return m, textarea.Blink, nil
 		}
 	}
 	return m, nil, nil
 }
 
-func (m *PartDetailModel) View(width, height int) string {
+func (m *PartDetailModel) openZoom() (*PartDetailModel, tea.Cmd, *Screen) {
+	if m.img == nil {
+		return m, nil, nil
+	}
+	title := m.part.PartNumber
+	if m.diagram != nil {
+		title = m.diagram.ID
+	}
+	m.zoom = newZoomView(m.imgPath, title)
+	return m, tea.Batch(clearImage(m.img.ID()), tea.ClearScreen), nil
+}
+
+// updateZoom handles input while the diagram is zoomed; any click,
+// z or esc returns to the part view
+func (m *PartDetailModel) updateZoom(msg tea.Msg) (*PartDetailModel, tea.Cmd, *Screen) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if !ui.IsZoom(msg) && !ui.IsBack(msg) {
+			return m, nil, nil
+		}
+	case tea.MouseMsg:
+		if !ui.IsClick(msg) {
+			return m, nil, nil
+		}
+	default:
+		return m, nil, nil
+	}
+
+	id := m.zoom.ImageID()
+	m.zoom = nil
+	return m, tea.Batch(clearImage(id), tea.ClearScreen), nil
+}
+
+func (m *PartDetailModel) View(width, height int, layout ui.SplitLayout) string {
 	if width == 0 {
 		width = 80
 	}
@@ -258,6 +593,13 @@ func (m *PartDetailModel) View(width, height int) string {
 		height = 24
 	}
 
+	if m.zoom != nil {
+		return m.zoom.View(width, height)
+	}
+	if m.editor != nil {
+		return m.editor.View(width, height)
+	}
+
 	var result strings.Builder
 
 	// Top margin (2 blank lines to match other pages)
@@ -274,18 +616,22 @@ func (m *PartDetailModel) View(width, height int) string {
 		splitHeight = 10
 	}
 
-	leftContent := m.renderDiagram(splitHeight)
+	leftPane, rightPane := layout.Panes(width-2, splitHeight)
+	m.infoPane = rightPane
+	leftContent := m.renderDiagram(leftPane)
 	rightContent := m.renderPartInfo()
 
-	split := ui.RenderSplitPane(leftContent, rightContent, width-2, splitHeight)
+	split := ui.RenderSplitPane(leftContent, rightContent, width-2, splitHeight, layout)
 
 	// Output image escape with positioning
 	// Save cursor, move to image position, render, restore cursor
-	if m.img != nil {
+	if m.img != nil && leftPane.Width > 0 {
+		cols, rows := m.img.Fit(leftPane.Width, leftPane.Height-1)
+		m.imgRect = ui.Rect{X: leftPane.X, Y: splitTop + leftPane.Y + 1, Width: cols, Height: rows}
 		result.WriteString("\x1b7")   // Save cursor position
 		result.WriteString("  ")      // Left padding (matches split pane margin)
 		result.WriteString("\x1b[1B") // Move cursor down 1 line (past diagram ID)
-		result.WriteString(m.img.Render())
+		result.WriteString(m.img.RenderSize(cols, rows))
 		result.WriteString("\x1b8") // Restore cursor position
 	}
 
@@ -294,18 +640,18 @@ func (m *PartDetailModel) View(width, height int) string {
 	return result.String()
 }
 
-func (m *PartDetailModel) renderDiagram(height int) string {
+func (m *PartDetailModel) renderDiagram(pane ui.Rect) string {
 	var lines []string
 
 	if m.img != nil {
 		// Add diagram ID above the image, truncated to image width
 		if m.diagram != nil {
-			maxWidth := m.img.CellWidth()
+			maxWidth, _ := m.img.Fit(pane.Width, pane.Height-1)
 			diagramID := lipgloss.NewStyle().MaxWidth(maxWidth).Render(m.diagram.ID)
 			lines = append(lines, ui.DimStyle.Render(diagramID))
 		}
 		// Image is rendered separately in View(), just add placeholder lines
-		imgHeight := m.img.CellHeight()
+		_, imgHeight := m.img.Fit(pane.Width, pane.Height-1)
 		for i := 0; i < imgHeight; i++ {
 			lines = append(lines, "")
 		}
@@ -316,7 +662,7 @@ func (m *PartDetailModel) renderDiagram(height int) string {
 	}
 
 	// Pad to fill height
-	for len(lines) < height {
+	for len(lines) < pane.Height {
 		lines = append(lines, "")
 	}
 
@@ -325,6 +671,7 @@ func (m *PartDetailModel) renderDiagram(height int) string {
 
 func (m *PartDetailModel) renderPartInfo() string {
 	var b strings.Builder
+	m.itemRows = m.itemRows[:0]
 
 	// Header - show GROUP > SUBGROUP breadcrumb
 	title := "UNKNOWN"
@@ -355,9 +702,24 @@ func (m *PartDetailModel) renderPartInfo() string {
 		b.WriteString(m.fieldLine("Quantity", fmt.Sprintf("%d", *m.part.Quantity)))
 	}
 	m.renderField(&b, "Spec", m.part.Spec)
-	m.renderField(&b, "Color", m.part.Color)
+	if color, match := m.fit.color(m.part.Part); match {
+		_, which := m.fit.vehicle.ColorMatch(m.part.Part)
+		b.WriteString(m.fieldLine("Color", ui.MatchStyle.Render(color+" - matches this van's "+which)))
+	} else if color != "" {
+		b.WriteString(m.fieldLine("Color", color))
+	}
 	m.renderField(&b, "Date Range", m.part.ModelDateRange)
 	m.renderField(&b, "Replaces", m.part.ReplacementPartNumber)
+	if attrs := db.PartAttributes(m.part.Part); len(attrs) > 0 {
+		var values []string
+		for _, a := range attrs {
+			values = append(values, a.Value)
+		}
+		b.WriteString(m.fieldLine("Applies to", strings.Join(values, " · ")))
+	}
+	if m.fitReason != "" {
+		b.WriteString(m.fieldLine("Fits", ui.ErrorStyle.Render("No, "+m.fitReason)))
+	}
 
 	if m.part.Notes != nil {
 		b.WriteString("\n")
@@ -367,22 +729,42 @@ func (m *PartDetailModel) renderPartInfo() string {
 		b.WriteString("\n")
 	}
 
-	// User note
-	if m.note != nil && !m.editingNote {
+	// Notes journal, oldest first
+	if len(m.notes) > 0 {
 		b.WriteString("\n")
-		b.WriteString(ui.DimStyle.Render("My Note:"))
-		b.WriteString("\n")
-		b.WriteString(*m.note)
+		b.WriteString(ui.DimStyle.Render("My Notes:"))
 		b.WriteString("\n")
+		for i, n := range m.notes {
+			m.itemRows = append(m.itemRows, strings.Count(b.String(), "\n"))
+			b.WriteString(m.noteHeader(n, i == m.cursor))
+			b.WriteString("\n")
+			b.WriteString(noteExcerpt(m.renderNote(n)))
+			b.WriteString("\n")
+		}
 	}
 
-	// Note editor
-	if m.editingNote {
-		b.WriteString("\n")
-		b.WriteString(ui.DimStyle.Render("My Note:"))
+	// Attachments
+	if len(m.attachments) > 0 || m.attaching {
 		b.WriteString("\n")
-		b.WriteString(m.noteInput.View())
+		b.WriteString(ui.DimStyle.Render("Attachments:"))
 		b.WriteString("\n")
+		for i, a := range m.attachments {
+			m.itemRows = append(m.itemRows, strings.Count(b.String(), "\n"))
+			info := ui.DimStyle.Render(fmt.Sprintf(" %s  %s", formatSize(a.Size), formatTimestamp(a.CreatedAt)))
+			if m.attachmentOffset()+i == m.cursor {
+				b.WriteString(ui.SelectedStyle.Render("> "))
+				b.WriteString(ui.SelectedLabelStyle.Render(a.Name))
+			} else {
+				b.WriteString("  ")
+				b.WriteString(a.Name)
+			}
+			b.WriteString(info)
+			b.WriteString("\n")
+		}
+		if m.attaching {
+			b.WriteString(ui.BoxStyle.Render("Attach: " + m.attachInput.View()))
+			b.WriteString("\n")
+		}
 	}
 
 	b.WriteString("\n")
@@ -395,26 +777,41 @@ func (m *PartDetailModel) renderPartInfo() string {
 		b.WriteString("\n")
 		for i, sg := range m.subgroups {
 			label := fmt.Sprintf("%s > %s", strings.ToUpper(sg.GroupName), strings.ToUpper(sg.SubgroupName))
-			if i == m.cursor {
+			m.itemRows = append(m.itemRows, strings.Count(b.String(), "\n"))
+			if m.subgroupOffset()+i == m.cursor {
 				b.WriteString(ui.SelectedStyle.Render("> "))
 				b.WriteString(ui.SelectedLabelStyle.Render(label))
+			} else if m.unfit[i] != "" {
+				b.WriteString("  ")
+				b.WriteString(ui.DimStyle.Render(label))
 			} else {
 				b.WriteString("  ")
 				b.WriteString(label)
 			}
+			if m.unfit[i] != "" {
+				b.WriteString(ui.DimStyle.Render(" - doesn't fit: " + m.unfit[i]))
+			}
 			b.WriteString("\n")
 		}
 		b.WriteString("\n")
 	}
+	if m.hiddenSubs > 0 {
+		noun := "subgroups"
+		if m.hiddenSubs == 1 {
+			noun = "subgroup"
+		}
+		b.WriteString(ui.DimStyle.Render(fmt.Sprintf("Hidden: %d %s where it doesn't fit", m.hiddenSubs, noun)))
+		b.WriteString("\n\n")
+	}
 
 	// Links
 	b.WriteString(ui.DimStyle.Render("Links:"))
 	b.WriteString("\n")
 
-	linkLabels := []string{"EPC", "Amayama", "Amazon"}
-	for i, url := range m.links {
-		cursorIdx := len(m.subgroups) + i
-		label := linkLabels[i]
+	for i, link := range m.links {
+		cursorIdx := m.linkOffset() + i
+		label, url := link.label, link.url
+		m.itemRows = append(m.itemRows, strings.Count(b.String(), "\n"))
 		if cursorIdx == m.cursor {
 			b.WriteString(ui.SelectedStyle.Render("> "))
 			b.WriteString(ui.SelectedLabelStyle.Render(label))
@@ -432,23 +829,76 @@ func (m *PartDetailModel) renderPartInfo() string {
 	b.WriteString("\n")
 
 	// Footer
-	if m.editingNote {
-		b.WriteString(ui.DimStyle.Render("ctrl+s save   esc cancel"))
-	} else {
-		bookmarkAction := "bookmark"
-		if m.isBookmark {
-			bookmarkAction = "unbookmark"
-		}
-		noteAction := "note"
-		if m.note != nil {
-			noteAction = "edit note"
-		}
-		b.WriteString(ui.DimStyle.Render(fmt.Sprintf("esc back   ↑↓ navigate   enter select   b %s   n %s", bookmarkAction, noteAction)))
+	bookmarkAction := "bookmark"
+	if m.isBookmark {
+		bookmarkAction = "unbookmark"
+	}
+	switch {
+	case m.status != "":
+		b.WriteString(ui.ErrorStyle.Render(m.status))
+	case m.attaching:
+		b.WriteString(ui.DimStyle.Render("enter attach   esc cancel"))
+	case strings.HasPrefix(m.confirmDelete, "note:"):
+		b.WriteString(ui.ErrorStyle.Render("press x again to delete this note"))
+	case m.confirmDelete != "":
+		b.WriteString(ui.ErrorStyle.Render("press x again to delete this attachment and its file"))
+	case m.selectedAttachment() != nil:
+		b.WriteString(ui.DimStyle.Render(fmt.Sprintf("esc back   ↑↓ navigate   enter view   x delete   b %s   a attach   z zoom", bookmarkAction)))
+	case m.selectedNote() != nil:
+		b.WriteString(ui.DimStyle.Render(fmt.Sprintf("esc back   ↑↓ navigate   enter edit   e $EDITOR   x delete   b %s   n add note   z zoom", bookmarkAction)))
+	default:
+		b.WriteString(ui.DimStyle.Render(fmt.Sprintf("esc back   ↑↓ navigate   enter select   [ ] prev/next   b %s   n add note   e $EDITOR   a attach   z zoom", bookmarkAction)))
 	}
 
 	return b.String()
 }
 
+// noteHeader renders a journal entry's timestamp, author and tags
+func (m *PartDetailModel) noteHeader(n db.Note, selected bool) string {
+	parts := []string{formatTimestamp(n.CreatedAt)}
+	if n.Author != "" {
+		parts = append(parts, n.Author)
+	}
+	for _, t := range n.Tags {
+		parts = append(parts, "#"+t)
+	}
+	header := strings.Join(parts, " · ")
+
+	if selected {
+		return ui.SelectedStyle.Render("> ") + ui.SelectedLabelStyle.Render(header)
+	}
+	return "  " + ui.DimStyle.Render(header)
+}
+
+// renderNote formats a note as Markdown, caching the result until the
+// notes or the pane width change
+func (m *PartDetailModel) renderNote(n db.Note) string {
+	width := max(m.infoPane.Width-2, 20)
+	if m.renderedNotes == nil || m.renderedWidth != width {
+		m.renderedNotes = make(map[int]string)
+		m.renderedWidth = width
+	}
+	if r, ok := m.renderedNotes[n.ID]; ok {
+		return r
+	}
+	r := ui.RenderMarkdown(n.Content, width)
+	m.renderedNotes[n.ID] = r
+	return r
+}
+
+// noteExcerpt indents the first lines of a note, marking any that are cut
+func noteExcerpt(content string) string {
+	const maxLines = 6
+	lines := strings.Split(content, "\n")
+	if len(lines) > maxLines {
+		lines = append(lines[:maxLines], ui.DimStyle.Render("…"))
+	}
+	for i, line := range lines {
+		lines[i] = "  " + line
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (m *PartDetailModel) renderField(b *strings.Builder, label string, value *string) {
 	if value == nil {
 		return
@@ -457,11 +907,14 @@ func (m *PartDetailModel) renderField(b *strings.Builder, label string, value *s
 }
 
 func (m *PartDetailModel) fieldLine(label, value string) string {
-	labelStyle := lipgloss.NewStyle().Width(16).Foreground(ui.ColorDim)
+	labelStyle := ui.DimStyle.Width(16)
 	return labelStyle.Render(label) + value + "\n"
 }
 
 func (m *PartDetailModel) ImageID() uint32 {
+	if m.zoom != nil {
+		return m.zoom.ImageID()
+	}
 	if m.img != nil {
 		return m.img.ID()
 	}
@@ -1,5 +1,12 @@
 package model
 
+import (
+	"fmt"
+
+	"delica-tui/db"
+	"delica-tui/ui"
+)
+
 type ScreenType int
 
 const (
@@ -10,15 +17,77 @@ const (
 	ScreenSearch
 	ScreenBookmarks
 	ScreenNotes
+	ScreenJumps
+	ScreenOrder
+	ScreenSheet
+	ScreenStats
 )
 
+// String returns the name used for the screen type in user config.
+func (t ScreenType) String() string {
+	switch t {
+	case ScreenHome:
+		return "home"
+	case ScreenGroup:
+		return "group"
+	case ScreenSubgroup:
+		return "subgroup"
+	case ScreenPartDetail:
+		return "part"
+	case ScreenSearch:
+		return "search"
+	case ScreenBookmarks:
+		return "bookmarks"
+	case ScreenNotes:
+		return "notes"
+	case ScreenJumps:
+		return "jumps"
+	case ScreenOrder:
+		return "order"
+	case ScreenSheet:
+		return "sheet"
+	case ScreenStats:
+		return "stats"
+	}
+	return "unknown"
+}
+
 type Screen struct {
 	Type       ScreenType
 	GroupID    string
 	SubgroupID string
 	PartID     int
 	Query      string
+	Filter     db.SearchFilter
 	FromSearch bool
+
+	// Selection when the screen was left, restored by back and forward.
+	// ItemID is the selected menu item, which is found again even if the
+	// list has changed; Cursor is used when it can't be.
+	Cursor int
+	ItemID string
+	Page   int
+}
+
+// key identifies the place a screen shows, apart from its selection
+func (s Screen) key() string {
+	return fmt.Sprintf("%s:%s:%s:%d", s.Type, s.GroupID, s.SubgroupID, s.PartID)
+}
+
+// saveSelection records the item under a menu's cursor
+func (s *Screen) saveSelection(menu *ui.Menu) {
+	s.Cursor = menu.Cursor
+	s.ItemID = ""
+	if item := menu.Selected(); item != nil {
+		s.ItemID = item.ID
+	}
+}
+
+// restoreSelection moves a menu's cursor back to the recorded item
+func (s Screen) restoreSelection(menu *ui.Menu) {
+	if !menu.SelectID(s.ItemID) {
+		menu.SetCursor(s.Cursor)
+	}
 }
 
 func HomeScreen() Screen {
@@ -41,6 +110,10 @@ func SearchScreen(query string) Screen {
 	return Screen{Type: ScreenSearch, Query: query}
 }
 
+func FilteredSearchScreen(query string, filter db.SearchFilter) Screen {
+	return Screen{Type: ScreenSearch, Query: query, Filter: filter}
+}
+
 func BookmarksScreen() Screen {
 	return Screen{Type: ScreenBookmarks}
 }
@@ -48,3 +121,19 @@ func BookmarksScreen() Screen {
 func NotesScreen() Screen {
 	return Screen{Type: ScreenNotes}
 }
+
+func JumpsScreen() Screen {
+	return Screen{Type: ScreenJumps}
+}
+
+func OrderScreen() Screen {
+	return Screen{Type: ScreenOrder}
+}
+
+func SheetScreen(groupID string) Screen {
+	return Screen{Type: ScreenSheet, GroupID: groupID}
+}
+
+func StatsScreen() Screen {
+	return Screen{Type: ScreenStats}
+}
@@ -2,9 +2,11 @@ package model
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
+	"delica-tui/bom"
 	"delica-tui/db"
 	"delica-tui/ui"
 
@@ -13,21 +15,65 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// searchPageSize is the number of results shown per page
+const searchPageSize = 50
+
 type SearchModel struct {
 	db            *db.DB
+	dataPath      string
 	input         textinput.Model
 	results       []db.SearchResult
-	cursor        int
+	suggestion    string // Spelling-corrected query, when fuzzy matching kicked in
+	menu          *ui.Menu
 	lastQuery     string
 	debounceTimer *time.Timer
+
+	// Filtering and paging
+	filter      db.SearchFilter
+	facets      []db.Facet
+	page        int
+	total       int
+	focusFacets bool // Keys move through the facet sidebar instead of results
+	facetCursor int
+	facetPane   ui.Rect // Screen area of the facet sidebar
+	facetRows   []int   // Screen row of each facet, for clicks
+
+	// Recent queries, newest first, and the one recalled into the input
+	history      []string
+	historyIndex int
+
+	// Prompt for the name of a saved search
+	naming    bool
+	nameInput textinput.Model
+	status    string
+
+	// e was pressed on a facet; the next key picks the export format
+	exporting bool
+
+	// Results picked for a batch action
+	batch batch
+
+	// Results on this page that don't fit the vehicle
+	unfit int
+	fit   fit
+}
+
+// searchResults is one page of results for a query, with its facets
+type searchResults struct {
+	filter db.SearchFilter
+	page   int
+	result *db.SearchPage
+	facets []db.Facet
+	hidden int // Results on all pages left out because they don't fit
 }
 
-type searchResultsMsg struct {
-	query   string
-	results []db.SearchResult
+// searchDebounceMsg arrives a moment after the query was typed. The search
+// runs if the query hasn't changed since.
+type searchDebounceMsg struct {
+	query string
 }
 
-func NewSearchModel(database *db.DB, query string) *SearchModel {
+func NewSearchModel(database *db.DB, dataPath, query string, filter db.SearchFilter, fit fit) *SearchModel {
 	ti := textinput.New()
 	ti.Placeholder = "Search parts by number or description..."
 	ti.Focus()
@@ -35,48 +81,259 @@ func NewSearchModel(database *db.DB, query string) *SearchModel {
 	ti.CharLimit = 100
 	ti.Width = 50
 
+	history, _ := database.GetSearchHistory()
+
+	ni := textinput.New()
+	ni.Prompt = ""
+	ni.Placeholder = "Name this search..."
+	ni.CharLimit = 50
+	ni.Width = 40
+
 	m := &SearchModel{
-		db:    database,
-		input: ti,
+		db:           database,
+		dataPath:     dataPath,
+		input:        ti,
+		filter:       filter,
+		history:      history,
+		historyIndex: -1,
+		nameInput:    ni,
+		fit:          fit,
 	}
 
 	// Initial search if query provided
 	if query != "" {
-		m.results, _ = database.SearchParts(query)
 		m.lastQuery = query
+		m.applyResults(m.search(query, m.filter, 0))
+	} else {
+		m.setResults(nil)
 	}
 
 	return m
 }
 
+// search runs a query for one page of results along with its facets. When
+// parts that don't fit are hidden, every result is fetched and the fitting
+// ones are paged, so pages are full and the total counts only those shown.
+func (m *SearchModel) search(query string, filter db.SearchFilter, page int) searchResults {
+	match, _ := m.db.ResolveSearch(query, filter)
+	facets, _ := m.db.SearchFacets(match, filter)
+	if !m.fit.hides() {
+		result, _ := m.db.SearchPartsPage(match, filter, page*searchPageSize, searchPageSize)
+		return searchResults{filter: filter, page: page, result: result, facets: facets}
+	}
+
+	result, _ := m.db.SearchPartsPage(match, filter, 0, -1)
+	hidden := 0
+	if result != nil {
+		var fits []db.SearchResult
+		for _, r := range result.Results {
+			if ok, _ := m.fit.check(r.Part); ok {
+				fits = append(fits, r)
+			} else {
+				hidden++
+			}
+		}
+		start := min(page*searchPageSize, len(fits))
+		result.Total = len(fits)
+		result.Results = fits[start:min(start+searchPageSize, len(fits))]
+	}
+	return searchResults{filter: filter, page: page, result: result, facets: facets, hidden: hidden}
+}
+
+// rerun runs the current query again after a filter or page change
+func (m *SearchModel) rerun() {
+	m.applyResults(m.search(m.input.Value(), m.filter, m.page))
+}
+
+func (m *SearchModel) applyResults(msg searchResults) {
+	m.page = msg.page
+	m.facets = keepActiveFacets(msg.facets, m.facets, msg.filter)
+	if m.facetCursor >= len(m.facets) {
+		m.facetCursor = max(len(m.facets)-1, 0)
+	}
+	if len(m.facets) == 0 {
+		m.focusFacets = false
+	}
+
+	m.suggestion, m.total = "", 0
+	var results []db.SearchResult
+	if msg.result != nil {
+		m.suggestion = msg.result.Suggestion
+		m.total = msg.result.Total
+		results = msg.result.Results
+	}
+	m.setResults(results)
+	m.unfit += msg.hidden
+}
+
+// keepActiveFacets adds active facets from the previous results that no
+// longer match anything, so a filter can always be turned off again
+func keepActiveFacets(facets, prev []db.Facet, filter db.SearchFilter) []db.Facet {
+	for _, p := range prev {
+		if !filter.Active(p) || slices.ContainsFunc(facets, func(f db.Facet) bool {
+			return f.Kind == p.Kind && f.Value == p.Value
+		}) {
+			continue
+		}
+		p.Count = 0
+		facets = append(facets, p)
+	}
+
+	// Keep facets of the same kind together
+	slices.SortStableFunc(facets, func(a, b db.Facet) int {
+		return facetKindIndex(a.Kind) - facetKindIndex(b.Kind)
+	})
+	return facets
+}
+
+// setResults replaces the results list and resets the cursor
+func (m *SearchModel) setResults(results []db.SearchResult) {
+	m.results = results
+	m.unfit = 0
+
+	var items []ui.MenuItem
+	for _, r := range results {
+		ok, reason := m.fit.check(r.Part)
+		if !ok {
+			m.unfit++
+			if m.fit.hides() {
+				continue
+			}
+		}
+
+		// Part number
+		label := r.PartNumber
+		if r.PNC != nil {
+			label = fmt.Sprintf("[%s] %s", *r.PNC, r.PartNumber)
+		}
+
+		// Hint: description + location
+		var hintParts []string
+		if r.Note != nil {
+			// Show the note the query matched in place of the description
+			hintParts = append(hintParts, "note: "+noteSnippet(*r.Note))
+		} else if r.Description != nil {
+			hintParts = append(hintParts, *r.Description)
+		}
+		if r.SubgroupName != nil {
+			hintParts = append(hintParts, *r.SubgroupName)
+		} else {
+			hintParts = append(hintParts, r.GroupName)
+		}
+		if r.Fuzzy {
+			// Mark hits that only matched the corrected spelling
+			label = "~" + label
+		}
+		hint := strings.Join(hintParts, " - ")
+		if !ok {
+			hint = unfitHint(hint, reason)
+		}
+
+		items = append(items, ui.MenuItem{
+			ID:    fmt.Sprintf("%d", r.ID),
+			Label: label,
+			Hint:  hint,
+			Dim:   !ok,
+		})
+	}
+
+	m.menu = ui.NewMenu(items)
+	m.menu.LabelWidth = 24
+}
+
+// pages returns the number of result pages
+func (m *SearchModel) pages() int {
+	return (m.total + searchPageSize - 1) / searchPageSize
+}
+
 func (m *SearchModel) Update(msg tea.Msg) (*SearchModel, tea.Cmd, *Screen) {
 	var cmd tea.Cmd
 
+	if m.naming {
+		return m.updateNaming(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		m.status = ""
+		if m.exporting {
+			m.exporting = false
+			m.status = exportBOM(m.facetBOM, m.dataPath, msg)
+			return m, nil, nil
+		}
+		if ui.IsSaveSearch(msg) {
+			if strings.TrimSpace(m.input.Value()) != "" {
+				m.naming = true
+				m.nameInput.SetValue(m.input.Value())
+				m.nameInput.CursorEnd()
+				m.input.Blur()
+				return m, m.nameInput.Focus(), nil
+			}
+			return m, nil, nil
+		}
+		if !m.focusFacets {
+			if handled, cmd := m.batch.update(m.db, m.dataPath, m.menu, msg, true); handled {
+				return m, cmd, nil
+			}
+		}
+		if m.historyIndex >= 0 || m.input.Value() == "" {
+			if handled, cmd := m.updateHistory(msg); handled {
+				return m, cmd, nil
+			}
+		}
+		if ui.IsTab(msg) {
+			m.focusFacets = !m.focusFacets && len(m.facets) > 0
+			return m, nil, nil
+		}
+		if ui.IsNextPage(msg) {
+			if m.page+1 < m.pages() {
+				m.page++
+				m.rerun()
+			}
+			return m, nil, nil
+		}
+		if ui.IsPrevPage(msg) {
+			if m.page > 0 {
+				m.page--
+				m.rerun()
+			}
+			return m, nil, nil
+		}
+		if m.focusFacets {
+			return m.updateFacets(msg)
+		}
+
 		// Navigation with arrow keys only (j/k should type into input)
 		if msg.Type == tea.KeyUp {
-			if m.cursor > 0 {
-				m.cursor--
-			}
+			m.menu.Up()
 			return m, nil, nil
 		}
 		if msg.Type == tea.KeyDown {
-			if m.cursor < len(m.results)-1 {
-				m.cursor++
-			}
+			m.menu.Down()
 			return m, nil, nil
 		}
-		if ui.IsEnter(msg) && len(m.results) > 0 {
-			result := m.results[m.cursor]
-			s := PartDetailScreen(result.ID, true)
-			return m, nil, &s
+		if ui.IsEnter(msg) {
+			return m.openSelected()
 		}
 
-	case searchResultsMsg:
+	case tea.MouseMsg:
+		if ui.IsClick(msg) {
+			for i, row := range m.facetRows {
+				if row == msg.Y && m.facetPane.Contains(msg.X, msg.Y-splitTop) {
+					m.facetCursor = i
+					m.toggleFacet()
+					return m, nil, nil
+				}
+			}
+		}
+		if m.menu.Mouse(msg) {
+			return m.openSelected()
+		}
+		return m, nil, nil
+
+	case searchDebounceMsg:
 		if msg.query == m.input.Value() {
-			m.results = msg.results
-			m.cursor = 0
+			m.applyResults(m.search(msg.query, m.filter, 0))
 		}
 		return m, nil, nil
 	}
@@ -85,19 +342,148 @@ func (m *SearchModel) Update(msg tea.Msg) (*SearchModel, tea.Cmd, *Screen) {
 	prevValue := m.input.Value()
 	m.input, cmd = m.input.Update(msg)
 
-	// Debounced search on input change
+	// Debounced search on input change, starting again from the first page
 	if m.input.Value() != prevValue {
 		query := m.input.Value()
+		m.page = 0
+		m.historyIndex = -1
 		return m, tea.Tick(150*time.Millisecond, func(t time.Time) tea.Msg {
-			results, _ := m.db.SearchParts(query)
-			return searchResultsMsg{query: query, results: results}
+			return searchDebounceMsg{query: query}
 		}), nil
 	}
 
 	return m, cmd, nil
 }
 
-func (m *SearchModel) View(width, height int) string {
+// updateHistory steps through recent queries with the arrow keys. Browsing
+// starts from an empty input and ends with enter, which keeps the recalled
+// query so the arrows select results again.
+func (m *SearchModel) updateHistory(msg tea.KeyMsg) (bool, tea.Cmd) {
+	index := m.historyIndex
+	switch {
+	case msg.Type == tea.KeyUp:
+		if index+1 >= len(m.history) {
+			return index >= 0, nil
+		}
+		index++
+	case msg.Type == tea.KeyDown && index >= 0:
+		index--
+	case ui.IsEnter(msg) && index >= 0:
+		m.historyIndex = -1
+		return true, nil
+	default:
+		return false, nil
+	}
+
+	m.historyIndex = index
+	m.page = 0
+	if index < 0 {
+		m.input.SetValue("")
+		m.setResults(nil)
+		m.facets = nil
+		return true, nil
+	}
+	m.input.SetValue(m.history[index])
+	m.input.CursorEnd()
+	m.rerun()
+	return true, nil
+}
+
+// updateNaming handles input while the save prompt is open
+func (m *SearchModel) updateNaming(msg tea.Msg) (*SearchModel, tea.Cmd, *Screen) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case ui.IsBack(msg):
+			return m, m.closeNaming(), nil
+		case ui.IsEnter(msg):
+			name := strings.TrimSpace(m.nameInput.Value())
+			if name == "" {
+				return m, nil, nil
+			}
+			query := strings.TrimSpace(m.input.Value())
+			if err := m.db.SaveSearch(name, query, m.filter); err != nil {
+				m.status = ui.ErrorStyle.Render("Save failed: " + err.Error())
+			} else {
+				m.db.AddSearchHistory(query)
+				m.status = ui.SelectedStyle.Render(fmt.Sprintf("Saved \"%s\" to home", name))
+			}
+			return m, m.closeNaming(), nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.nameInput, cmd = m.nameInput.Update(msg)
+	return m, cmd, nil
+}
+
+func (m *SearchModel) closeNaming() tea.Cmd {
+	m.naming = false
+	m.nameInput.Blur()
+	return m.input.Focus()
+}
+
+// updateFacets handles keys while the facet sidebar has focus
+func (m *SearchModel) updateFacets(msg tea.KeyMsg) (*SearchModel, tea.Cmd, *Screen) {
+	switch {
+	case ui.IsUp(msg):
+		if m.facetCursor > 0 {
+			m.facetCursor--
+		}
+	case ui.IsDown(msg):
+		if m.facetCursor < len(m.facets)-1 {
+			m.facetCursor++
+		}
+	case ui.IsEnter(msg), ui.IsToggle(msg):
+		m.toggleFacet()
+		return m, nil, nil
+	case ui.IsExport(msg):
+		if m.facetCursor < len(m.facets) {
+			switch m.facets[m.facetCursor].Kind {
+			case db.FacetGroup, db.FacetSubgroup, db.FacetTag:
+				m.exporting = true
+			}
+		}
+	}
+	return m, nil, nil
+}
+
+// facetBOM builds the bill of materials for the group, subgroup or tag
+// under the facet cursor, across the whole catalog rather than the results
+func (m *SearchModel) facetBOM() (*bom.BOM, error) {
+	facet := m.facets[m.facetCursor]
+	switch facet.Kind {
+	case db.FacetGroup:
+		return bom.ForGroup(m.db, m.dataPath, facet.Value)
+	case db.FacetSubgroup:
+		return bom.ForSubgroup(m.db, m.dataPath, facet.Value)
+	}
+	return bom.ForTag(m.db, m.dataPath, facet.Value)
+}
+
+// toggleFacet turns the facet under the cursor on or off and re-queries
+func (m *SearchModel) toggleFacet() {
+	if m.facetCursor >= len(m.facets) {
+		return
+	}
+	m.filter.Toggle(m.facets[m.facetCursor])
+	m.page = 0
+	m.rerun()
+}
+
+// openSelected navigates to the part under the cursor
+func (m *SearchModel) openSelected() (*SearchModel, tea.Cmd, *Screen) {
+	if item := m.menu.Selected(); item != nil {
+		m.db.AddSearchHistory(m.input.Value())
+
+		var partID int
+		fmt.Sscanf(item.ID, "%d", &partID)
+		s := PartDetailScreen(partID, true)
+		return m, nil, &s
+	}
+	return m, nil, nil
+}
+
+func (m *SearchModel) View(width, height int, layout ui.SplitLayout) string {
 	if width == 0 {
 		width = 80
 	}
@@ -119,15 +505,97 @@ func (m *SearchModel) View(width, height int) string {
 		splitHeight = 10
 	}
 
-	leftContent := m.renderLeftPane(splitHeight)
-	rightContent := m.renderRightPane(splitHeight)
+	leftPane, rightPane := layout.Panes(width-2, splitHeight)
+	leftContent := m.renderLeftPane(leftPane)
+	rightContent := m.renderRightPane(rightPane)
 
-	split := ui.RenderSplitPane(leftContent, rightContent, width-2, splitHeight)
+	split := ui.RenderSplitPane(leftContent, rightContent, width-2, splitHeight, layout)
 
 	return header + "\n" + split
 }
 
-func (m *SearchModel) renderLeftPane(height int) string {
+// facetSections lists facet kinds in display order with their headings
+var facetSections = []struct {
+	kind  db.FacetKind
+	title string
+}{
+	{db.FacetGroup, "GROUP"},
+	{db.FacetSubgroup, "SUBGROUP"},
+	{db.FacetTag, "TAG"},
+	{db.FacetColor, "COLOR"},
+	{db.FacetAttribute, "SPEC"},
+	{db.FacetReplacement, "OTHER"},
+	{db.FacetBookmarked, "OTHER"},
+}
+
+func facetKindIndex(kind db.FacetKind) int {
+	for i, s := range facetSections {
+		if s.kind == kind {
+			return i
+		}
+	}
+	return len(facetSections)
+}
+
+func (m *SearchModel) renderLeftPane(pane ui.Rect) string {
+	m.facetPane = pane
+	m.facetRows = m.facetRows[:0]
+
+	if strings.TrimSpace(m.input.Value()) == "" || len(m.facets) == 0 {
+		return m.renderTips(pane.Height)
+	}
+
+	var lines []string
+	lines = append(lines, ui.HeaderStyle.Render("FILTERS")+"   "+ui.DimStyle.Render("tab focus"))
+
+	cursorLine := 0
+	title := ""
+	for i, f := range m.facets {
+		if t := facetSections[min(facetKindIndex(f.Kind), len(facetSections)-1)].title; t != title {
+			title = t
+			lines = append(lines, "", ui.DimStyle.Render(title))
+		}
+
+		check := "[ ] "
+		if m.filter.Active(f) {
+			check = ui.SelectedStyle.Render("[x] ")
+		}
+		count := fmt.Sprintf("%d", f.Count)
+		label := truncate(f.Label, pane.Width-len(count)-8)
+
+		prefix := "  "
+		labelStyle := ui.NormalLabelStyle
+		if m.focusFacets && i == m.facetCursor {
+			prefix = ui.SelectedStyle.Render("› ")
+			labelStyle = ui.SelectedLabelStyle
+			cursorLine = len(lines)
+		}
+
+		line := prefix + check + labelStyle.Render(label)
+		gap := pane.Width - lipgloss.Width(line) - len(count) - 1
+		line += strings.Repeat(" ", max(gap, 1)) + ui.CountStyle.Render(count)
+
+		m.facetRows = append(m.facetRows, len(lines))
+		lines = append(lines, line)
+	}
+
+	// Scroll so the cursor stays in view
+	offset := 0
+	if len(lines) > pane.Height {
+		offset = min(max(cursorLine-pane.Height/2, 0), len(lines)-pane.Height)
+		lines = lines[offset : offset+pane.Height]
+	}
+	for i, row := range m.facetRows {
+		m.facetRows[i] = -1
+		if row >= offset && row < offset+len(lines) {
+			m.facetRows[i] = splitTop + pane.Y + row - offset
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m *SearchModel) renderTips(height int) string {
 	var lines []string
 
 	// Search tips
@@ -149,82 +617,114 @@ func (m *SearchModel) renderLeftPane(height int) string {
 	return strings.Join(lines, "\n")
 }
 
-func (m *SearchModel) renderRightPane(height int) string {
+func (m *SearchModel) renderRightPane(pane ui.Rect) string {
 	var b strings.Builder
 
 	// Input box
 	inputBox := ui.BoxStyle.Render(m.input.View())
 	b.WriteString(inputBox)
-	b.WriteString("\n\n")
+	b.WriteString("\n")
+
+	if m.naming {
+		b.WriteString(ui.BoxStyle.Render("Save as: " + m.nameInput.View()))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
 
 	b.WriteString(ui.DimStyle.Render("─────────────────────────────────"))
 	b.WriteString("\n\n")
 
 	// Results
 	query := strings.TrimSpace(m.input.Value())
+	if query != "" && m.suggestion != "" {
+		b.WriteString(ui.DimStyle.Render("Did you mean ") + ui.PartNumberStyle.Render(m.suggestion) + ui.DimStyle.Render("?"))
+		b.WriteString("\n\n")
+	}
+
 	if query == "" {
 		b.WriteString(ui.DimStyle.Render("Start typing to search parts"))
+		if len(m.history) > 0 {
+			b.WriteString("\n" + ui.DimStyle.Render("↑ for recent searches"))
+		}
 	} else if len(m.results) == 0 {
-		b.WriteString(ui.DimStyle.Render(fmt.Sprintf("No results for \"%s\"", query)))
+		empty := fmt.Sprintf("No results for \"%s\"", query)
+		if m.unfit > 0 {
+			empty += " · " + unfitCount(m.unfit, m.fit)
+		}
+		b.WriteString(ui.DimStyle.Render(empty))
 	} else {
-		maxResults := height - 8
+		maxResults := pane.Height - 8
 		if maxResults < 5 {
 			maxResults = 5
 		}
 		if maxResults > 20 {
 			maxResults = 20
 		}
+		if len(m.menu.Items) < maxResults {
+			maxResults = max(len(m.menu.Items), 1)
+		}
+		m.menu.MaxVisibleItems = maxResults
 
-		for i, r := range m.results {
-			if i >= maxResults {
-				break
-			}
-
-			isSelected := i == m.cursor
-
-			// Part number
-			label := r.PartNumber
-			if r.PNC != nil {
-				label = fmt.Sprintf("[%s] %s", *r.PNC, r.PartNumber)
-			}
-
-			// Hint: description + location
-			var hintParts []string
-			if r.Description != nil {
-				hintParts = append(hintParts, *r.Description)
-			}
-			if r.SubgroupName != nil {
-				hintParts = append(hintParts, *r.SubgroupName)
-			} else {
-				hintParts = append(hintParts, r.GroupName)
-			}
-			hint := strings.Join(hintParts, " - ")
-
-			var line string
-			if isSelected {
-				line = ui.SelectedStyle.Render("> ")
-			} else {
-				line = "  "
-			}
+		m.menu.SetOrigin(pane.X, splitTop+pane.Y+strings.Count(b.String(), "\n"), pane.Width)
+		b.WriteString(m.menu.View())
 
-			labelStyle := lipgloss.NewStyle().Width(24)
-			if isSelected {
-				line += ui.SelectedLabelStyle.Render(labelStyle.Render(strings.ToUpper(label)))
-			} else {
-				line += ui.NormalLabelStyle.Render(labelStyle.Render(strings.ToUpper(label)))
+		b.WriteString("\n\n")
+		status := fmt.Sprintf("%d results", m.total)
+		if m.total == 1 {
+			status = "1 result"
+		}
+		if m.pages() > 1 {
+			status = fmt.Sprintf("Page %d of %d · %s", m.page+1, m.pages(), status)
+		}
+		if m.unfit > 0 {
+			status += " · " + unfitCount(m.unfit, m.fit)
+			if m.pages() > 1 && !m.fit.hides() {
+				status += " on this page"
 			}
-			line += ui.DimStyle.Render(strings.ToUpper(hint))
-
-			b.WriteString(line)
-			b.WriteString("\n")
 		}
+		b.WriteString(ui.DimStyle.Render(status))
+	}
 
-		b.WriteString("\n")
-		b.WriteString(ui.DimStyle.Render(fmt.Sprintf("%d results", len(m.results))))
+	if m.status != "" {
+		b.WriteString("\n\n" + m.status)
 	}
 
 	b.WriteString("\n\n")
-	b.WriteString(ui.DimStyle.Render("↑↓ select   enter view"))
+	help := "↑↓ select   enter view   ctrl+t pick   ctrl+s save"
+	switch {
+	case m.naming:
+		help = "enter save   esc cancel"
+	case m.exporting:
+		help = exportHelp
+	case m.historyIndex >= 0:
+		help = fmt.Sprintf("↑↓ recent searches (%d/%d)   enter keep", m.historyIndex+1, len(m.history))
+	case m.focusFacets:
+		help = "↑↓ move   space toggle   e export   tab results"
+	case m.batch.footer(m.menu, true) != "":
+		help = m.batch.footer(m.menu, true)
+	}
+	if m.pages() > 1 && !m.naming {
+		help += "   pgup/pgdn page"
+	}
+	b.WriteString(ui.DimStyle.Render(help))
 
 	return b.String()
 }
+
+// noteSnippet returns the first line of a note, shortened for a hint
+func noteSnippet(note string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(note), "\n")
+	return truncate(line, 40)
+}
+
+// truncate shortens s to at most width runes, marking the cut with "..."
+func truncate(s string, width int) string {
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+	if width <= 3 {
+		return string(r[:max(width, 0)])
+	}
+	return string(r[:width-3]) + "..."
+}
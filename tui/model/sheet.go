@@ -0,0 +1,321 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"delica-tui/db"
+	"delica-tui/image"
+	"delica-tui/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Contact sheet tiles: a diagram above its subgroup's name, with a gap
+// before the next tile across and below
+const (
+	sheetTileCols = 24
+	sheetGap      = 2
+	sheetTop      = 4 // Rows above the grid: top margin, hint, title and a blank
+)
+
+// SheetModel tiles the diagrams of every subgroup in a group, loading them
+// as they scroll into view
+type SheetModel struct {
+	db        *db.DB
+	groupID   string
+	group     *db.Group
+	subgroups []db.Subgroup
+	dataPath  string
+	thumbs    thumbnails
+	cursor    int
+
+	// Grid layout from the last render, and the first tile row shown
+	cols   int
+	rows   int
+	scroll int
+
+	// Images drawn by the last render, and those sent to the terminal,
+	// which are only placed again on later renders
+	shown []uint32
+	sent  map[uint32]bool
+}
+
+func NewSheetModel(database *db.DB, groupID string, dataPath string, thumbs thumbnails) *SheetModel {
+	group, _ := database.GetGroup(groupID)
+	subgroups, _ := database.GetSubgroups(groupID)
+	return &SheetModel{
+		db:        database,
+		groupID:   groupID,
+		group:     group,
+		subgroups: subgroups,
+		dataPath:  dataPath,
+		thumbs:    thumbs,
+		sent:      make(map[uint32]bool),
+		cols:      1,
+		rows:      1,
+	}
+}
+
+// tileRows returns the height of a tile's diagram in cells. Diagrams are
+// around 4:3, so the rows follow from the terminal's cell shape.
+func tileRows() int {
+	cw, ch := image.CellSize()
+	return max(sheetTileCols*cw*3/(4*ch), 3)
+}
+
+// tileHeight is a tile's diagram plus its label and the gap below
+func tileHeight() int {
+	return tileRows() + 2
+}
+
+func (m *SheetModel) Update(msg tea.Msg) (*SheetModel, tea.Cmd, *Screen) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case ui.IsLeft(msg):
+			m.move(-1)
+		case ui.IsRight(msg):
+			m.move(1)
+		case ui.IsUp(msg):
+			m.move(-m.cols)
+		case ui.IsDown(msg):
+			m.move(m.cols)
+		case ui.IsEnter(msg):
+			return m.openSelected()
+		}
+
+	case tea.MouseMsg:
+		switch {
+		case ui.IsWheelUp(msg):
+			m.move(-m.cols)
+		case ui.IsWheelDown(msg):
+			m.move(m.cols)
+		case ui.IsClick(msg):
+			if i := m.tileAt(msg.X, msg.Y); i >= 0 {
+				m.cursor = i
+				return m.openSelected()
+			}
+		}
+	}
+	return m, m.loadVisible(), nil
+}
+
+// move shifts the cursor, stopping at the first and last tiles, and
+// scrolls to keep it in view
+func (m *SheetModel) move(delta int) {
+	m.cursor = max(min(m.cursor+delta, len(m.subgroups)-1), 0)
+	m.scrollToCursor()
+}
+
+func (m *SheetModel) scrollToCursor() {
+	row := m.cursor / m.cols
+	if row < m.scroll {
+		m.scroll = row
+	}
+	if row >= m.scroll+m.rows {
+		m.scroll = row - m.rows + 1
+	}
+}
+
+// openSelected navigates to the subgroup under the cursor
+func (m *SheetModel) openSelected() (*SheetModel, tea.Cmd, *Screen) {
+	if m.cursor < len(m.subgroups) {
+		s := SubgroupScreen(m.subgroups[m.cursor].ID)
+		return m, nil, &s
+	}
+	return m, nil, nil
+}
+
+// tileAt returns the index of the tile drawn at screen position x, y, or -1
+func (m *SheetModel) tileAt(x, y int) int {
+	x -= 2
+	y -= sheetTop
+	if x < 0 || y < 0 {
+		return -1
+	}
+	col, row := x/(sheetTileCols+sheetGap), y/tileHeight()
+	if col >= m.cols || row >= m.rows {
+		return -1
+	}
+	if i := (m.scroll+row)*m.cols + col; i < len(m.subgroups) {
+		return i
+	}
+	return -1
+}
+
+// visible returns the range of tiles on screen
+func (m *SheetModel) visible() (first, end int) {
+	first = m.scroll * m.cols
+	end = min(first+m.rows*m.cols, len(m.subgroups))
+	return first, end
+}
+
+// loadVisible starts loading the next thumbnail on screen, nearest the
+// cursor first. Thumbnails load one at a time, so each one loaded asks for
+// the next through the model.
+func (m *SheetModel) loadVisible() tea.Cmd {
+	first, end := m.visible()
+	for d := 0; d < end-first; d++ {
+		for _, i := range []int{m.cursor + d, m.cursor - d} {
+			if i < first || i >= end {
+				continue
+			}
+			if cmd := m.thumbs.load(m.db, m.dataPath, m.subgroups[i].ID); cmd != nil {
+				return cmd
+			}
+		}
+	}
+	return nil
+}
+
+// ImageID returns a value that clears every image when the sheet shows
+// any, as it draws one per tile
+func (m *SheetModel) ImageID() uint32 {
+	if len(m.shown) > 0 {
+		return 0xFFFFFFFF
+	}
+	return 0
+}
+
+// imagesCleared forgets the diagrams sent, after every image was deleted
+func (m *SheetModel) imagesCleared() {
+	m.shown = nil
+	clear(m.sent)
+}
+
+func (m *SheetModel) View(width, height int, layout ui.SplitLayout) string {
+	if width == 0 {
+		width = 80
+	}
+	if height == 0 {
+		height = 24
+	}
+
+	// Fit as many tiles as the terminal holds
+	m.cols = max((width-2+sheetGap)/(sheetTileCols+sheetGap), 1)
+	m.rows = max((height-sheetTop-2)/tileHeight(), 1)
+	m.scrollToCursor()
+
+	// Top margin with hint
+	headerStyle := lipgloss.NewStyle().
+		Width(width - 2).
+		Padding(1, 1, 0, 1).
+		Align(lipgloss.Right)
+
+	var b strings.Builder
+	b.WriteString(headerStyle.Render(ui.DimStyle.Render("esc back")))
+	b.WriteString("\n")
+
+	// Hide images that scrolled away, then place the visible ones; the
+	// first line is left blank for the breadcrumbs
+	first, end := m.visible()
+	images, drawn := m.renderImages(first, end)
+	for _, id := range m.shown {
+		if !slices.Contains(drawn, id) {
+			b.WriteString(image.Hide(id))
+		}
+	}
+	m.shown = drawn
+	b.WriteString(images)
+
+	title := "UNKNOWN"
+	if m.group != nil {
+		title = strings.ToUpper(m.group.Name)
+	}
+	b.WriteString("  " + ui.HeaderStyle.Render(title))
+	b.WriteString(ui.DimStyle.Render(fmt.Sprintf("  %d diagrams", len(m.subgroups))))
+	b.WriteString("\n\n")
+
+	if len(m.subgroups) == 0 {
+		b.WriteString("  " + ui.DimStyle.Render("No subgroups found"))
+		return b.String()
+	}
+
+	for row := 0; row*m.cols < end-first; row++ {
+		b.WriteString(m.renderTileRow(first+row*m.cols, min(first+(row+1)*m.cols, end)))
+	}
+
+	help := "←↑↓→ move   enter open"
+	if first > 0 || end < len(m.subgroups) {
+		help += fmt.Sprintf("   %d-%d of %d", first+1, end, len(m.subgroups))
+	}
+	b.WriteString("\n  " + ui.DimStyle.Render(help))
+
+	return b.String()
+}
+
+// renderTileRow draws one row of tiles: a placeholder for each diagram,
+// then the subgroup names
+func (m *SheetModel) renderTileRow(from, to int) string {
+	gap := strings.Repeat(" ", sheetGap)
+	lines := make([]string, tileHeight())
+	for i := range lines {
+		lines[i] = "  "
+	}
+
+	for i := from; i < to; i++ {
+		thumb, ok := m.thumbs[m.subgroups[i].ID]
+		note := ""
+		switch {
+		case !ok || thumb == nil:
+			note = ui.DimStyle.Render("Loading...")
+		case thumb.img == nil && thumb.err != "":
+			note = ui.ErrorStyle.Render("Image missing")
+		case thumb.img == nil:
+			note = ui.DimStyle.Render("No diagram")
+		}
+		for r := 0; r < tileRows(); r++ {
+			cell := ""
+			if r == 0 {
+				cell = note
+			}
+			lines[r] += padCell(cell) + gap
+		}
+
+		name := ansi.Truncate(strings.ToUpper(m.subgroups[i].Name), sheetTileCols-2, "…")
+		label := "  " + ui.NormalLabelStyle.Render(name)
+		if i == m.cursor {
+			label = ui.SelectedStyle.Render("› ") + ui.SelectedLabelStyle.Render(name)
+		}
+		lines[tileRows()] += padCell(label) + gap
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// renderImages places the loaded diagrams of the visible tiles, each in
+// its own spot, returning the escape sequences and the images drawn.
+// Each diagram is sent the first time it is shown.
+func (m *SheetModel) renderImages(first, end int) (string, []uint32) {
+	cw, ch := image.CellSize()
+	var b strings.Builder
+	var drawn []uint32
+	for i := first; i < end; i++ {
+		thumb := m.thumbs[m.subgroups[i].ID]
+		if thumb == nil || thumb.img == nil {
+			continue
+		}
+		row := sheetTop + (i-first)/m.cols*tileHeight()
+		col := 2 + (i-first)%m.cols*(sheetTileCols+sheetGap)
+		cols, rows := thumb.img.FitCells(sheetTileCols, tileRows(), cw, ch)
+
+		if !m.sent[thumb.img.ID()] {
+			b.WriteString(thumb.img.Transmit())
+			m.sent[thumb.img.ID()] = true
+		}
+		b.WriteString("\x1b7")                                  // Save cursor position
+		b.WriteString(fmt.Sprintf("\x1b[%d;%dH", row+1, col+1)) // Move to the tile
+		b.WriteString(thumb.img.Place(cols, rows))
+		b.WriteString("\x1b8") // Restore cursor position
+		drawn = append(drawn, thumb.img.ID())
+	}
+	return b.String(), drawn
+}
+
+// padCell pads styled text to the width of a tile
+func padCell(s string) string {
+	return s + strings.Repeat(" ", max(sheetTileCols-lipgloss.Width(s), 0))
+}
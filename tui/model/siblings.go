@@ -0,0 +1,157 @@
+package model
+
+import (
+	"slices"
+
+	"delica-tui/db"
+	"delica-tui/image"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// siblings holds the subgroup screens next to the current one, built ahead
+// of time so ] and [ open them without waiting, by subgroup ID
+type siblings map[string]*SubgroupModel
+
+// prefetchMsg asks for the neighbours of a subgroup to be built, once the
+// screen showing it has been drawn
+type prefetchMsg struct {
+	subgroupID string
+}
+
+// diagramMsg delivers a subgroup diagram loaded in the background
+type diagramMsg struct {
+	subgroupID string
+	img        *image.KittyImage
+	err        error
+}
+
+// currentSubgroup returns the subgroup shown, or the one the part shown is
+// in, or nil on other screens
+func (m *Model) currentSubgroup() *db.Subgroup {
+	switch m.screen.Type {
+	case ScreenSubgroup:
+		return m.subgroup.subgroup
+	case ScreenPartDetail:
+		return m.partDetail.subgroup
+	}
+	return nil
+}
+
+// neighbours returns the subgroups before and after one in its group, in
+// the order the group lists them ("" at either end)
+func (m *Model) neighbours(subgroup *db.Subgroup) (prev, next string) {
+	subgroups, _ := m.db.GetSubgroups(subgroup.GroupID)
+	i := slices.IndexFunc(subgroups, func(s db.Subgroup) bool { return s.ID == subgroup.ID })
+	if i < 0 {
+		return "", ""
+	}
+	if i > 0 {
+		prev = subgroups[i-1].ID
+	}
+	if i < len(subgroups)-1 {
+		next = subgroups[i+1].ID
+	}
+	return prev, next
+}
+
+// stepSubgroup opens the next (step 1) or previous (step -1) subgroup in
+// the group. The step replaces the current screen rather than adding to
+// history, so esc still returns to where the walk started; from a part,
+// the subgroup it was opened from is replaced too.
+func (m *Model) stepSubgroup(step int) (*Model, tea.Cmd) {
+	current := m.currentSubgroup()
+	if current == nil {
+		return m, nil
+	}
+	prev, next := m.neighbours(current)
+	to := next
+	if step < 0 {
+		to = prev
+	}
+	if to == "" {
+		m.notice = "Last subgroup in the group"
+		if step < 0 {
+			m.notice = "First subgroup in the group"
+		}
+		return m, nil
+	}
+
+	m.leave()
+	if m.screen.Type == ScreenSubgroup {
+		// Keep this screen for stepping back
+		m.siblings[current.ID] = m.subgroup
+	}
+	if n := len(m.history); m.screen.Type == ScreenPartDetail && n > 0 &&
+		m.history[n-1].Type == ScreenSubgroup && m.history[n-1].SubgroupID == current.ID {
+		m.history = m.history[:n-1]
+	}
+	if n := len(m.history); n > 0 && m.history[n-1].Type == ScreenGroup && m.history[n-1].GroupID == current.GroupID {
+		// Going back to the group selects the subgroup walked to
+		m.history[n-1].ItemID = to
+	}
+	m.forward = nil
+	m.screen = SubgroupScreen(to)
+	m.initScreen(false)
+
+	return m, tea.Batch(tea.ClearScreen, m.screenCmd())
+}
+
+// takeSibling returns the prefetched screen for a subgroup, removing it
+// from the cache, or nil if there isn't one
+func (m *Model) takeSibling(subgroupID string) *SubgroupModel {
+	sub := m.siblings[subgroupID]
+	delete(m.siblings, subgroupID)
+	return sub
+}
+
+// prefetchSiblings asks for the current subgroup's neighbours to be built,
+// unless they already are. The message arrives after the screen is drawn,
+// so reading them doesn't hold it up.
+func (m *Model) prefetchSiblings() tea.Cmd {
+	current := m.currentSubgroup()
+	if current == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		return prefetchMsg{subgroupID: current.ID}
+	}
+}
+
+// buildSiblings builds the neighbours of the current subgroup and starts
+// loading their diagrams, dropping screens that are no longer next to it.
+// The database is read here, as it can't be used from a command.
+func (m *Model) buildSiblings(msg prefetchMsg) tea.Cmd {
+	current := m.currentSubgroup()
+	if current == nil || current.ID != msg.subgroupID {
+		return nil
+	}
+	prev, next := m.neighbours(current)
+	for id := range m.siblings {
+		if id != prev && id != next {
+			delete(m.siblings, id)
+		}
+	}
+
+	var cmds []tea.Cmd
+	for _, id := range []string{next, prev} {
+		if _, ok := m.siblings[id]; ok || id == "" {
+			continue
+		}
+		sub := newSubgroupModel(m.db, id, m.dataPath, m.fit)
+		m.siblings[id] = sub
+		cmds = append(cmds, sub.loadDiagram())
+	}
+	return tea.Batch(cmds...)
+}
+
+// setDiagram hands a loaded diagram to the screen waiting on it, whether
+// it is still prefetched or already shown
+func (m *Model) setDiagram(msg diagramMsg) {
+	if sub := m.siblings[msg.subgroupID]; sub != nil && sub.loading {
+		sub.setDiagram(msg.img, msg.err)
+	}
+	if m.screen.Type == ScreenSubgroup && m.subgroup.subgroupID == msg.subgroupID && m.subgroup.loading {
+		m.subgroup.setDiagram(msg.img, msg.err)
+	}
+}
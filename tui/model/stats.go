@@ -0,0 +1,292 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"delica-tui/db"
+	"delica-tui/stats"
+	"delica-tui/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// statsSections are the lists the stats screen pages through with tab
+var statsSections = []string{"Subgroups", "Scrape failures", "No diagram", "Missing images", "Replaced", "Tags"}
+
+// StatsModel shows how complete the catalog is: its totals and the scrape's
+// progress beside one list at a time of what it holds or lacks
+type StatsModel struct {
+	db      *db.DB
+	report  *stats.Report
+	err     string
+	section int
+	menu    *ui.Menu
+	targets map[string]Screen // Where each menu item leads, by item ID
+}
+
+func NewStatsModel(database *db.DB, dataPath string, section int) *StatsModel {
+	m := &StatsModel{db: database}
+	report, err := stats.Build(database, dataPath)
+	if err != nil {
+		m.err = err.Error()
+		report = &stats.Report{}
+	}
+	m.report = report
+	m.setSection(section)
+	return m
+}
+
+// setSection lists a section of the report, the first if it is out of range
+func (m *StatsModel) setSection(section int) {
+	if section < 0 || section >= len(statsSections) {
+		section = 0
+	}
+	m.section = section
+	m.targets = make(map[string]Screen)
+
+	var items []ui.MenuItem
+	add := func(label, hint string, to *Screen) {
+		id := fmt.Sprintf("%d", len(items))
+		items = append(items, ui.MenuItem{ID: id, Label: label, Hint: hint})
+		if to != nil {
+			m.targets[id] = *to
+		}
+	}
+	part := func(p stats.Part, hint string) {
+		s := PartDetailScreen(p.ID, false)
+		add(p.PartNumber, hint, &s)
+	}
+
+	r := m.report
+	switch statsSections[section] {
+	case "Subgroups":
+		for _, g := range r.Groups {
+			s := GroupScreen(g.ID)
+			add(g.Name, fmt.Sprintf("%s, %s", countParts(g.Parts), countDiagrams(g.Diagrams)), &s)
+			for _, sub := range g.Subgroups {
+				hint := fmt.Sprintf("%s, %s", countParts(sub.Parts), countDiagrams(sub.Diagrams))
+				if sub.Parts == 0 {
+					hint = "empty - " + hint
+				}
+				s := SubgroupScreen(sub.ID)
+				add("  "+sub.Name, hint, &s)
+			}
+		}
+	case "Scrape failures":
+		if r.Scrape != nil {
+			for _, f := range r.Scrape.Failures {
+				add(f.URL, f.Error, nil)
+			}
+		}
+	case "No diagram":
+		for _, p := range r.NoDiagram {
+			part(p, strings.TrimPrefix(p.Description+" - "+p.Location, " - "))
+		}
+	case "Missing images":
+		for _, img := range r.MissingImages {
+			var to *Screen
+			if img.SubgroupID != "" {
+				s := SubgroupScreen(img.SubgroupID)
+				to = &s
+			}
+			path := img.Path
+			if path == "" {
+				path = "no image recorded"
+			}
+			add(img.DiagramID, path, to)
+		}
+	case "Replaced":
+		for _, p := range r.Replaced {
+			part(p, "→ "+p.Replacement+" - "+p.Location)
+		}
+	case "Tags":
+		for _, t := range r.Tags {
+			add(t.Name, fmt.Sprintf("%s - %s", t.Category, countParts(t.Parts)), nil)
+		}
+	}
+
+	m.menu = ui.NewMenu(items)
+	m.menu.LabelWidth = 24
+
+	// URLs, errors and file paths are shown as recorded
+	switch statsSections[section] {
+	case "Scrape failures", "Missing images":
+		m.menu.KeepCase = true
+	}
+}
+
+func countDiagrams(n int) string {
+	if n == 1 {
+		return "1 diagram"
+	}
+	return fmt.Sprintf("%d diagrams", n)
+}
+
+func (m *StatsModel) Update(msg tea.Msg) (*StatsModel, tea.Cmd, *Screen) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if ui.IsUp(msg) {
+			m.menu.Up()
+		}
+		if ui.IsDown(msg) {
+			m.menu.Down()
+		}
+		if ui.IsEnter(msg) {
+			return m.openSelected()
+		}
+		if ui.IsTab(msg) {
+			m.setSection((m.section + 1) % len(statsSections))
+		}
+		if ui.IsBackTab(msg) {
+			m.setSection((m.section + len(statsSections) - 1) % len(statsSections))
+		}
+
+	case tea.MouseMsg:
+		if m.menu.Mouse(msg) {
+			return m.openSelected()
+		}
+	}
+	return m, nil, nil
+}
+
+// openSelected navigates to the group, subgroup or part under the cursor
+func (m *StatsModel) openSelected() (*StatsModel, tea.Cmd, *Screen) {
+	if item := m.menu.Selected(); item != nil {
+		if s, ok := m.targets[item.ID]; ok {
+			return m, nil, &s
+		}
+	}
+	return m, nil, nil
+}
+
+func (m *StatsModel) View(width, height int, layout ui.SplitLayout) string {
+	if width == 0 {
+		width = 80
+	}
+	if height == 0 {
+		height = 24
+	}
+
+	// Header
+	headerStyle := lipgloss.NewStyle().
+		Width(width-2).
+		Padding(1, 1, 0, 1).
+		Align(lipgloss.Right)
+
+	header := headerStyle.Render(ui.DimStyle.Render("esc back"))
+
+	// Split pane content
+	splitHeight := height - 5
+	if splitHeight < 10 {
+		splitHeight = 10
+	}
+
+	leftPane, rightPane := layout.Panes(width-2, splitHeight)
+	leftContent := m.renderSummary(leftPane.Height)
+	rightContent := m.renderSection(rightPane)
+
+	split := ui.RenderSplitPane(leftContent, rightContent, width-2, splitHeight, layout)
+
+	return header + "\n" + split
+}
+
+// renderSummary shows the catalog's totals and the scrape's progress
+func (m *StatsModel) renderSummary(height int) string {
+	r := m.report
+	t := r.Totals
+	row := func(label, value string) string {
+		return ui.DimStyle.Width(16).Render(label) + value
+	}
+	problem := func(n int, one, many string) string {
+		switch n {
+		case 0:
+			return ""
+		case 1:
+			return ui.ErrorStyle.Render("  1 " + one)
+		}
+		return ui.ErrorStyle.Render(fmt.Sprintf("  %d %s", n, many))
+	}
+
+	var lines []string
+	lines = append(lines, ui.HeaderStyle.Render("CATALOG"))
+	lines = append(lines, "")
+	if m.err != "" {
+		lines = append(lines, ui.ErrorStyle.Render(m.err), "")
+	}
+	lines = append(lines, row("Groups", fmt.Sprintf("%d", t.Groups)))
+	lines = append(lines, row("Subgroups", fmt.Sprintf("%d", t.Subgroups)+problem(t.EmptySubgroups, "empty", "empty")))
+	lines = append(lines, row("Diagrams", fmt.Sprintf("%d", t.Diagrams)+problem(len(r.MissingImages), "image missing", "images missing")))
+	lines = append(lines, row("Parts", fmt.Sprintf("%d", t.Parts)+problem(len(r.NoDiagram), "without a diagram", "without diagrams")))
+	lines = append(lines, row("Replaced", fmt.Sprintf("%d", len(r.Replaced))))
+	lines = append(lines, row("Tagged", fmt.Sprintf("%d (%d%%)", t.TaggedParts, t.TagCoverage())))
+	lines = append(lines, "")
+
+	lines = append(lines, ui.HeaderStyle.Render("SCRAPE"))
+	lines = append(lines, "")
+	switch s := r.Scrape; {
+	case s == nil:
+		lines = append(lines, ui.DimStyle.Render("No scrape progress recorded"))
+	default:
+		lines = append(lines, row("Completed", fmt.Sprintf("%d", s.Completed)))
+		lines = append(lines, row("Failed", fmt.Sprintf("%d", s.Failed)))
+		lines = append(lines, row("Pending", fmt.Sprintf("%d", s.Pending)))
+		lines = append(lines, "")
+		if s.Complete() {
+			lines = append(lines, ui.SelectedStyle.Render("Every page was scraped"))
+		} else {
+			lines = append(lines, ui.ErrorStyle.Render("The scrape is incomplete;"))
+			lines = append(lines, ui.ErrorStyle.Render("empty subgroups may be missing parts"))
+		}
+	}
+
+	// Pad to fill height
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// renderSection shows the tabs and the list for the current section
+func (m *StatsModel) renderSection(pane ui.Rect) string {
+	var b strings.Builder
+
+	// Section title, and where it is among the sections
+	b.WriteString(ui.HeaderStyle.Render(strings.ToUpper(statsSections[m.section])))
+	b.WriteString(strings.Repeat(" ", 5))
+	b.WriteString(ui.CountStyle.Render(fmt.Sprintf("%d", len(m.menu.Items))))
+	b.WriteString(ui.DimStyle.Render(fmt.Sprintf("  %d of %d", m.section+1, len(statsSections))))
+	b.WriteString("\n")
+	b.WriteString(ui.DimStyle.Render("─────────────────────────────────"))
+
+	// Adjust menu visible items based on available height (max 15)
+	menuHeight := pane.Height - 5
+	if menuHeight < 5 {
+		menuHeight = 5
+	}
+	if menuHeight > 15 {
+		menuHeight = 15
+	}
+	m.menu.MaxVisibleItems = menuHeight
+
+	// One less blank line if menu scrolls (to account for scroll indicator)
+	if len(m.menu.Items) > m.menu.MaxVisibleItems {
+		b.WriteString("\n")
+	} else {
+		b.WriteString("\n\n")
+	}
+
+	if len(m.menu.Items) == 0 {
+		b.WriteString(ui.DimStyle.Render("Nothing to list"))
+	} else {
+		m.menu.SetOrigin(pane.X, splitTop+pane.Y+strings.Count(b.String(), "\n"), pane.Width)
+		b.WriteString(m.menu.View())
+	}
+
+	b.WriteString("\n\n")
+	b.WriteString(ui.DimStyle.Render("↑↓ navigate   enter open   tab next list   f filter"))
+
+	return b.String()
+}
@@ -1,10 +1,13 @@
 package model
 
 import (
+	"cmp"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"delica-tui/bom"
 	"delica-tui/db"
 	"delica-tui/image"
 	"delica-tui/ui"
@@ -12,6 +15,12 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// Subgroup diagrams are scaled to fit this many cells, then fitted to the pane
+const (
+	subgroupImageCols = 92
+	subgroupImageRows = 46
+)
+
 type SubgroupModel struct {
 	db         *db.DB
 	subgroupID string
@@ -21,10 +30,55 @@ type SubgroupModel struct {
 	diagram    *db.Diagram
 	menu       *ui.Menu
 	img        *image.KittyImage
+	imgPath    string
 	imgError   string
+	loading    bool    // The diagram is loading in the background
+	imgRect    ui.Rect // Screen area covered by the diagram, for click-to-zoom
+	zoom       *zoomView
+	dataPath   string
+	batch      batch
+	exporting  bool   // e was pressed; the next key picks the export format
+	status     string // Result of the last export
+	unfit      int    // Parts that don't fit the vehicle
+	fit        fit
+
+	// Attributes parsed from each part's spec and notes, the distinct ones
+	// to filter by, and the filter in use (-1 for none)
+	attrs   map[int][]db.Attribute
+	filters []db.Attribute
+	filter  int
+}
+
+// subgroupFilters lists the distinct attributes of a subgroup's parts,
+// kind by kind
+func subgroupFilters(attrs map[int][]db.Attribute) []db.Attribute {
+	var filters []db.Attribute
+	for _, list := range attrs {
+		for _, a := range list {
+			if !slices.Contains(filters, a) {
+				filters = append(filters, a)
+			}
+		}
+	}
+	slices.SortFunc(filters, func(a, b db.Attribute) int {
+		return cmp.Or(strings.Compare(a.Kind, b.Kind), strings.Compare(a.Value, b.Value))
+	})
+	return filters
 }
 
-func NewSubgroupModel(database *db.DB, subgroupID string, dataPath string) *SubgroupModel {
+func NewSubgroupModel(database *db.DB, subgroupID string, dataPath string, fit fit) *SubgroupModel {
+	m := newSubgroupModel(database, subgroupID, dataPath, fit)
+
+	// Load image - use larger size for better visibility
+	if m.imgPath != "" {
+		m.setDiagram(image.LoadAndScale(m.imgPath, subgroupImageCols, subgroupImageRows))
+	}
+	return m
+}
+
+// newSubgroupModel reads a subgroup's parts and diagram without loading
+// the image, which the caller loads now or in the background
+func newSubgroupModel(database *db.DB, subgroupID string, dataPath string, fit fit) *SubgroupModel {
 	subgroup, _ := database.GetSubgroup(subgroupID)
 	var group *db.Group
 	if subgroup != nil {
@@ -33,8 +87,68 @@ func NewSubgroupModel(database *db.DB, subgroupID string, dataPath string) *Subg
 	parts, _ := database.GetPartsForSubgroup(subgroupID)
 	diagram, _ := database.GetDiagramForSubgroup(subgroupID)
 
+	attrs, _ := database.GetSubgroupAttributes(subgroupID)
+
+	m := &SubgroupModel{
+		db:         database,
+		subgroupID: subgroupID,
+		subgroup:   subgroup,
+		group:      group,
+		parts:      parts,
+		diagram:    diagram,
+		dataPath:   dataPath,
+		fit:        fit,
+		attrs:      attrs,
+		filters:    subgroupFilters(attrs),
+		filter:     -1,
+	}
+	m.setItems()
+	if diagram != nil && diagram.ImagePath != nil {
+		m.imgPath = filepath.Join(dataPath, *diagram.ImagePath)
+	}
+	return m
+}
+
+// loadDiagram loads the diagram in the background, for a screen built
+// ahead of time. Only decoding and scaling happen in the command.
+func (m *SubgroupModel) loadDiagram() tea.Cmd {
+	if m.imgPath == "" {
+		return nil
+	}
+	m.loading = true
+	subgroupID, path := m.subgroupID, m.imgPath
+	return func() tea.Msg {
+		img, err := image.LoadAndScale(path, subgroupImageCols, subgroupImageRows)
+		return diagramMsg{subgroupID: subgroupID, img: img, err: err}
+	}
+}
+
+// setDiagram shows a loaded diagram, or why it couldn't be loaded
+func (m *SubgroupModel) setDiagram(img *image.KittyImage, err error) {
+	m.loading = false
+	if err != nil {
+		m.imgError = err.Error()
+		return
+	}
+	m.img = img
+}
+
+// setItems lists the parts that pass the attribute filter, naming their
+// colors and marking those that don't fit the vehicle. The cursor stays on
+// the same part if it is still listed.
+func (m *SubgroupModel) setItems() {
+	selected := ""
+	if m.menu != nil && m.menu.Selected() != nil {
+		selected = m.menu.Selected().ID
+	}
+
 	var items []ui.MenuItem
-	for _, p := range parts {
+	m.unfit = 0
+	for _, p := range m.parts {
+		if m.filter >= 0 && !slices.Contains(m.attrs[p.ID], m.filters[m.filter]) {
+			continue
+		}
+
 		label := p.PartNumber
 		if p.PNC != nil {
 			label = fmt.Sprintf("[%s] %s", *p.PNC, p.PartNumber)
@@ -43,35 +157,56 @@ func NewSubgroupModel(database *db.DB, subgroupID string, dataPath string) *Subg
 		if p.Description != nil {
 			hint = *p.Description
 		}
-		items = append(items, ui.MenuItem{ID: fmt.Sprintf("%d", p.ID), Label: label, Hint: hint})
-	}
 
-	m := &SubgroupModel{
-		db:         database,
-		subgroupID: subgroupID,
-		subgroup:   subgroup,
-		group:      group,
-		parts:      parts,
-		diagram:    diagram,
-		menu:       ui.NewMenu(items),
-	}
+		// Name the color variants, and pick out the van's
+		color, match := m.fit.color(p.Part)
+		if color != "" {
+			if match {
+				color += " (this van)"
+			}
+			hint = strings.TrimPrefix(hint+" - "+color, " - ")
+		}
 
-	// Load image - use larger size for better visibility
-	if diagram != nil && diagram.ImagePath != nil {
-		imgPath := filepath.Join(dataPath, *diagram.ImagePath)
-		if img, err := image.LoadAndScale(imgPath, 92, 46); err == nil {
-			m.img = img
-		} else {
-			m.imgError = err.Error()
+		ok, reason := m.fit.check(p.Part)
+		if !ok {
+			m.unfit++
+			if m.fit.hides() {
+				continue
+			}
+			hint = unfitHint(hint, reason)
 		}
+		items = append(items, ui.MenuItem{ID: fmt.Sprintf("%d", p.ID), Label: label, Hint: hint, Dim: !ok, Accent: match})
 	}
 
-	return m
+	m.menu = ui.NewMenu(items)
+	m.menu.SelectID(selected)
+}
+
+// cycleFilter steps through the attribute filters, then back to none
+func (m *SubgroupModel) cycleFilter(step int) {
+	n := len(m.filters) + 1
+	m.filter = (m.filter+1+step+n)%n - 1
+	m.setItems()
 }
 
 func (m *SubgroupModel) Update(msg tea.Msg) (*SubgroupModel, tea.Cmd, *Screen) {
+	if m.zoom != nil {
+		return m.updateZoom(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		m.status = ""
+		if m.exporting {
+			m.exporting = false
+			m.status = exportBOM(func() (*bom.BOM, error) {
+				return bom.ForSubgroup(m.db, m.dataPath, m.subgroupID)
+			}, m.dataPath, msg)
+			return m, nil, nil
+		}
+		if handled, cmd := m.batch.update(m.db, m.dataPath, m.menu, msg, false); handled {
+			return m, cmd, nil
+		}
 		if ui.IsUp(msg) {
 			m.menu.Up()
 		}
@@ -79,18 +214,73 @@ func (m *SubgroupModel) Update(msg tea.Msg) (*SubgroupModel, tea.Cmd, *Screen) {
 			m.menu.Down()
 		}
 		if ui.IsEnter(msg) {
-			if item := m.menu.Selected(); item != nil {
-				var partID int
-				fmt.Sscanf(item.ID, "%d", &partID)
-				s := PartDetailScreen(partID, false)
-				return m, nil, &s
-			}
+			return m.openSelected()
+		}
+		if ui.IsZoom(msg) {
+			return m.openZoom()
+		}
+		if ui.IsExport(msg) && len(m.parts) > 0 {
+			m.exporting = true
+		}
+		if ui.IsTab(msg) && len(m.filters) > 0 {
+			m.cycleFilter(1)
 		}
+		if ui.IsBackTab(msg) && len(m.filters) > 0 {
+			m.cycleFilter(-1)
+		}
+
+	case tea.MouseMsg:
+		if ui.IsClick(msg) && m.img != nil && m.imgRect.Contains(msg.X, msg.Y) {
+			return m.openZoom()
+		}
+		if m.menu.Mouse(msg) {
+			return m.openSelected()
+		}
+	}
+	return m, nil, nil
+}
+
+// openSelected navigates to the part under the cursor
+func (m *SubgroupModel) openSelected() (*SubgroupModel, tea.Cmd, *Screen) {
+	if item := m.menu.Selected(); item != nil {
+		var partID int
+		fmt.Sscanf(item.ID, "%d", &partID)
+		s := PartDetailScreen(partID, false)
+		return m, nil, &s
 	}
 	return m, nil, nil
 }
 
-func (m *SubgroupModel) View(width, height int) string {
+func (m *SubgroupModel) openZoom() (*SubgroupModel, tea.Cmd, *Screen) {
+	if m.img == nil {
+		return m, nil, nil
+	}
+	m.zoom = newZoomView(m.imgPath, m.diagram.ID)
+	return m, tea.Batch(clearImage(m.img.ID()), tea.ClearScreen), nil
+}
+
+// updateZoom handles input while the diagram is zoomed; any click,
+// z or esc returns to the split view
+func (m *SubgroupModel) updateZoom(msg tea.Msg) (*SubgroupModel, tea.Cmd, *Screen) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if !ui.IsZoom(msg) && !ui.IsBack(msg) {
+			return m, nil, nil
+		}
+	case tea.MouseMsg:
+		if !ui.IsClick(msg) {
+			return m, nil, nil
+		}
+	default:
+		return m, nil, nil
+	}
+
+	id := m.zoom.ImageID()
+	m.zoom = nil
+	return m, tea.Batch(clearImage(id), tea.ClearScreen), nil
+}
+
+func (m *SubgroupModel) View(width, height int, layout ui.SplitLayout) string {
 	if width == 0 {
 		width = 80
 	}
@@ -98,6 +288,10 @@ func (m *SubgroupModel) View(width, height int) string {
 		height = 24
 	}
 
+	if m.zoom != nil {
+		return m.zoom.View(width, height)
+	}
+
 	var result strings.Builder
 
 	// Top margin (2 blank lines to match other pages)
@@ -109,19 +303,22 @@ func (m *SubgroupModel) View(width, height int) string {
 		splitHeight = 10
 	}
 
-	leftContent := m.renderDiagram(splitHeight)
-	rightContent := m.renderPartsList(splitHeight)
+	leftPane, rightPane := layout.Panes(width-2, splitHeight)
+	leftContent := m.renderDiagram(leftPane)
+	rightContent := m.renderPartsList(rightPane)
 
-	split := ui.RenderSplitPane(leftContent, rightContent, width-2, splitHeight)
+	split := ui.RenderSplitPane(leftContent, rightContent, width-2, splitHeight, layout)
 
 	// Output image escape with positioning
 	// Save cursor, move to image position, render, restore cursor
-	if m.img != nil {
-		result.WriteString("\x1b7")    // Save cursor position
-		result.WriteString("  ")       // Left padding (matches split pane margin)
-		result.WriteString("\x1b[1B")  // Move cursor down 1 line (past diagram ID)
-		result.WriteString(m.img.Render())
-		result.WriteString("\x1b8")    // Restore cursor position
+	if m.img != nil && leftPane.Width > 0 {
+		cols, rows := m.img.Fit(leftPane.Width, leftPane.Height-1)
+		m.imgRect = ui.Rect{X: leftPane.X, Y: splitTop + leftPane.Y + 1, Width: cols, Height: rows}
+		result.WriteString("\x1b7")   // Save cursor position
+		result.WriteString("  ")      // Left padding (matches split pane margin)
+		result.WriteString("\x1b[1B") // Move cursor down 1 line (past diagram ID)
+		result.WriteString(m.img.RenderSize(cols, rows))
+		result.WriteString("\x1b8") // Restore cursor position
 	}
 
 	result.WriteString(split)
@@ -129,7 +326,7 @@ func (m *SubgroupModel) View(width, height int) string {
 	return result.String()
 }
 
-func (m *SubgroupModel) renderDiagram(height int) string {
+func (m *SubgroupModel) renderDiagram(pane ui.Rect) string {
 	var lines []string
 
 	if m.img != nil {
@@ -138,25 +335,27 @@ func (m *SubgroupModel) renderDiagram(height int) string {
 			lines = append(lines, ui.DimStyle.Render(m.diagram.ID))
 		}
 		// Image is rendered separately in View(), just add placeholder lines
-		imgHeight := m.img.CellHeight()
+		_, imgHeight := m.img.Fit(pane.Width, pane.Height-1)
 		for i := 0; i < imgHeight; i++ {
 			lines = append(lines, "")
 		}
 	} else if m.imgError != "" {
 		lines = append(lines, ui.ErrorStyle.Render(m.imgError))
+	} else if m.loading {
+		lines = append(lines, ui.DimStyle.Render("Loading diagram..."))
 	} else {
 		lines = append(lines, ui.DimStyle.Render("No diagram available"))
 	}
 
 	// Pad to fill height
-	for len(lines) < height {
+	for len(lines) < pane.Height {
 		lines = append(lines, "")
 	}
 
 	return strings.Join(lines, "\n")
 }
 
-func (m *SubgroupModel) renderPartsList(height int) string {
+func (m *SubgroupModel) renderPartsList(pane ui.Rect) string {
 	var b strings.Builder
 
 	// Header - show GROUP > SUBGROUP
@@ -167,12 +366,18 @@ func (m *SubgroupModel) renderPartsList(height int) string {
 	b.WriteString(ui.HeaderStyle.Render(title))
 	b.WriteString(strings.Repeat(" ", 5))
 	b.WriteString(ui.CountStyle.Render(fmt.Sprintf("%d", len(m.parts))))
+	if m.unfit > 0 {
+		b.WriteString(ui.DimStyle.Render("  " + unfitCount(m.unfit, m.fit)))
+	}
+	if m.filter >= 0 {
+		b.WriteString(ui.SelectedStyle.Render("  only " + m.filters[m.filter].Value))
+	}
 	b.WriteString("\n")
 	b.WriteString(ui.DimStyle.Render("─────────────────────────────────"))
 
 	// Adjust menu visible items based on available height (max 15)
 	// Header takes 3 lines, footer takes 2 lines
-	menuHeight := height - 5
+	menuHeight := pane.Height - 5
 	if menuHeight < 5 {
 		menuHeight = 5
 	}
@@ -190,17 +395,38 @@ func (m *SubgroupModel) renderPartsList(height int) string {
 
 	if len(m.parts) == 0 {
 		b.WriteString(ui.DimStyle.Render("No parts found"))
+	} else if len(m.menu.Items) == 0 && m.filter >= 0 {
+		b.WriteString(ui.DimStyle.Render("No parts for " + m.filters[m.filter].Value + " fit this van"))
+	} else if len(m.menu.Items) == 0 {
+		b.WriteString(ui.DimStyle.Render("No parts fit this van (ctrl+f to show them)"))
 	} else {
+		m.menu.SetOrigin(pane.X, splitTop+pane.Y+strings.Count(b.String(), "\n"), pane.Width)
 		b.WriteString(m.menu.View())
 	}
 
+	help := "↑↓ navigate   enter select   [ ] prev/next   f filter   space pick   z zoom   e export"
+	if len(m.filters) > 0 {
+		help += "   tab spec"
+	}
+	if footer := m.batch.footer(m.menu, false); footer != "" {
+		help = footer
+	}
+	switch {
+	case m.exporting:
+		help = exportHelp
+	case m.status != "":
+		help = m.status
+	}
 	b.WriteString("\n\n")
-	b.WriteString(ui.DimStyle.Render("↑↓ navigate   enter select"))
+	b.WriteString(ui.DimStyle.Render(help))
 
 	return b.String()
 }
 
 func (m *SubgroupModel) ImageID() uint32 {
+	if m.zoom != nil {
+		return m.zoom.ImageID()
+	}
 	if m.img != nil {
 		return m.img.ID()
 	}
@@ -0,0 +1,110 @@
+package model
+
+import (
+	"slices"
+
+	"delica-tui/config"
+	"delica-tui/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// themeAuto picks the light or dark theme to suit the terminal
+const themeAuto = "auto"
+
+// themeNames lists the themes T cycles through: auto, the built-in themes,
+// then the config's own in name order
+func themeNames(cfg *config.Config) []string {
+	names := []string{themeAuto}
+	for _, t := range ui.Themes {
+		names = append(names, t.Name)
+	}
+	var custom []string
+	for name := range cfg.Themes {
+		if !slices.Contains(names, name) {
+			custom = append(custom, name)
+		}
+	}
+	slices.Sort(custom)
+	return append(names, custom...)
+}
+
+// resolveTheme finds a theme by name. auto, and names that aren't known,
+// give the theme detected at startup.
+func resolveTheme(cfg *config.Config, name string, auto ui.Theme) ui.Theme {
+	if t, ok := ui.ThemeNamed(name); ok {
+		return t
+	}
+	c, ok := cfg.Themes[name]
+	if !ok {
+		return auto
+	}
+
+	t, ok := ui.ThemeNamed(c.Base)
+	if !ok {
+		t = ui.DarkTheme
+	}
+	t.Name = name
+	for _, f := range []struct {
+		color *lipgloss.Color
+		value string
+	}{
+		{&t.Header, c.Header},
+		{&t.PartNumber, c.PartNumber},
+		{&t.Selected, c.Selected},
+		{&t.SelectedLabel, c.SelectedLabel},
+		{&t.Text, c.Text},
+		{&t.Dim, c.Dim},
+		{&t.Count, c.Count},
+		{&t.Error, c.Error},
+		{&t.Link, c.Link},
+		{&t.Match, c.Match},
+	} {
+		if f.value != "" {
+			*f.color = lipgloss.Color(f.value)
+		}
+	}
+	return t
+}
+
+// detectTheme picks the theme auto stands for. NO_COLOR always gets mono.
+func detectTheme() ui.Theme {
+	if ui.NoColor() {
+		return ui.MonoTheme
+	}
+	return ui.DetectTheme()
+}
+
+// cycleTheme switches to the next theme
+func (m *Model) cycleTheme() (*Model, tea.Cmd) {
+	names := themeNames(m.config)
+	name := m.config.Theme
+	if name == "" {
+		name = themeAuto
+	}
+	i := slices.Index(names, name)
+	return m.setTheme(names[(i+1)%len(names)])
+}
+
+// setTheme switches to a theme by name, saves the choice and redraws
+func (m *Model) setTheme(name string) (*Model, tea.Cmd) {
+	if ui.NoColor() {
+		m.notice = "NO_COLOR is set, so there is no color to theme"
+		return m, nil
+	}
+
+	m.config.Theme = name
+	t := resolveTheme(m.config, name, m.autoTheme)
+	ui.SetTheme(t)
+
+	// Screens keep some rendered text, such as notes
+	m.leave()
+	m.initScreen(true)
+	m.notice = "Theme: " + name
+	if name == themeAuto {
+		m.notice += " (" + t.Name + ")"
+	}
+	m.saveConfig()
+	return m, tea.ClearScreen
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"path/filepath"
+
+	"delica-tui/db"
+	"delica-tui/image"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Thumbnails are scaled to fit this many cells, then fitted to the pane
+const (
+	thumbnailCols = 40
+	thumbnailRows = 20
+)
+
+// thumbnail is a subgroup's diagram scaled down for a preview
+type thumbnail struct {
+	diagramID string
+	img       *image.KittyImage // nil if the subgroup has no diagram, or it failed to load
+	err       string
+}
+
+// thumbnails caches diagram previews by subgroup ID for the session. A nil
+// entry is one still loading.
+type thumbnails map[string]*thumbnail
+
+// thumbnailMsg delivers a loaded thumbnail
+type thumbnailMsg struct {
+	subgroupID string
+	thumb      *thumbnail
+}
+
+// loading reports whether a thumbnail is being loaded
+func (t thumbnails) loading() bool {
+	for _, thumb := range t {
+		if thumb == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// load starts loading a subgroup's thumbnail in the background, unless it
+// is cached or another one is loading. The diagram is looked up here, as
+// the database can't be used from the command's goroutine; only decoding
+// and scaling the image happen there.
+func (t thumbnails) load(database *db.DB, dataPath, subgroupID string) tea.Cmd {
+	if _, ok := t[subgroupID]; ok || t.loading() {
+		return nil
+	}
+
+	diagram, err := database.GetDiagramForSubgroup(subgroupID)
+	switch {
+	case err != nil:
+		t[subgroupID] = &thumbnail{err: err.Error()}
+		return nil
+	case diagram == nil || diagram.ImagePath == nil:
+		t[subgroupID] = &thumbnail{}
+		return nil
+	}
+
+	t[subgroupID] = nil
+	path := filepath.Join(dataPath, *diagram.ImagePath)
+	return func() tea.Msg {
+		thumb := &thumbnail{diagramID: diagram.ID}
+		if img, err := image.LoadAndScale(path, thumbnailCols, thumbnailRows); err == nil {
+			thumb.img = img
+		} else {
+			thumb.err = err.Error()
+		}
+		return thumbnailMsg{subgroupID: subgroupID, thumb: thumb}
+	}
+}
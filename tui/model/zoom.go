@@ -0,0 +1,99 @@
+package model
+
+import (
+	"strings"
+
+	"delica-tui/image"
+	"delica-tui/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// clearImageMsg asks the main model to delete an image on the next render
+type clearImageMsg uint32
+
+func clearImage(id uint32) tea.Cmd {
+	return func() tea.Msg {
+		return clearImageMsg(id)
+	}
+}
+
+// zoomView shows a diagram scaled up to fill the screen.
+// The image is loaded at full terminal size the first time it is drawn.
+type zoomView struct {
+	path  string
+	title string
+	img   *image.KittyImage
+	err   string
+
+	// Size the image was loaded for
+	cols int
+	rows int
+}
+
+func newZoomView(path, title string) *zoomView {
+	return &zoomView{path: path, title: title}
+}
+
+func (z *zoomView) View(width, height int) string {
+	if width == 0 {
+		width = 80
+	}
+	if height == 0 {
+		height = 24
+	}
+
+	var result strings.Builder
+
+	// Reload at the new size when the terminal is resized
+	cols := width - 4
+	rows := height - splitTop - 3
+	if z.cols != cols || z.rows != rows {
+		if z.img != nil {
+			result.WriteString(image.Clear(z.img.ID()))
+		}
+		z.load(cols, rows)
+	}
+
+	// Top margin (2 blank lines to match other pages)
+	result.WriteString("\n\n")
+
+	header := lipgloss.NewStyle().Width(width - 4).Render(ui.DimStyle.Render(z.title))
+	result.WriteString("  " + header + "\n")
+
+	if z.img != nil {
+		imgCols, imgRows := z.img.Fit(cols, rows)
+		result.WriteString("\x1b7") // Save cursor position
+		result.WriteString("  ")    // Left padding (matches split pane margin)
+		result.WriteString(z.img.RenderSize(imgCols, imgRows))
+		result.WriteString("\x1b8") // Restore cursor position
+		result.WriteString(strings.Repeat("\n", imgRows))
+	} else {
+		result.WriteString("  " + ui.ErrorStyle.Render(z.err) + "\n")
+	}
+
+	result.WriteString("\n  " + ui.DimStyle.Render("z / esc / click close"))
+
+	return result.String()
+}
+
+func (z *zoomView) load(cols, rows int) {
+	z.cols, z.rows = cols, rows
+	img, err := image.LoadAndScale(z.path, cols, rows)
+	if err != nil {
+		z.img = nil
+		z.err = err.Error()
+		return
+	}
+	z.img = img
+	z.err = ""
+}
+
+// ImageID returns the ID of the zoomed image, if loaded
+func (z *zoomView) ImageID() uint32 {
+	if z.img != nil {
+		return z.img.ID()
+	}
+	return 0
+}
@@ -0,0 +1,215 @@
+// Package stats reports what the parts catalog holds, what it is missing,
+// and how far the scrape that built it got.
+package stats
+
+import (
+	"os"
+	"path/filepath"
+
+	"delica-tui/db"
+)
+
+// Formats lists the supported output formats
+var Formats = []string{"text", "json"}
+
+// Report is the catalog's statistics and coverage.
+type Report struct {
+	Totals        Totals  `json:"totals"`
+	Groups        []Group `json:"groups"`
+	NoDiagram     []Part  `json:"parts_without_diagrams"` // Parts whose diagram isn't in the catalog
+	MissingImages []Image `json:"missing_images"`         // Diagrams whose image file isn't on disk or isn't recorded
+	Replaced      []Part  `json:"replaced_parts"`
+	Tags          []Tag   `json:"tags"`
+	Scrape        *Scrape `json:"scrape_progress"` // nil if the database has no progress table
+}
+
+// Totals counts the whole catalog.
+type Totals struct {
+	Groups         int `json:"groups"`
+	Subgroups      int `json:"subgroups"`
+	EmptySubgroups int `json:"empty_subgroups"` // Subgroups without parts
+	Diagrams       int `json:"diagrams"`
+	Parts          int `json:"parts"`
+	TaggedParts    int `json:"tagged_parts"`
+}
+
+// Group is a group's counts and those of its subgroups.
+type Group struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Parts     int        `json:"parts"`
+	Diagrams  int        `json:"diagrams"`
+	Subgroups []Subgroup `json:"subgroups"`
+}
+
+// Subgroup is how many parts and diagrams a subgroup has.
+type Subgroup struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Parts    int    `json:"parts"`
+	Diagrams int    `json:"diagrams"`
+}
+
+// Part is a part listed in the report, and where it is.
+type Part struct {
+	ID          int    `json:"id"`
+	PartNumber  string `json:"part_number"`
+	Description string `json:"description,omitempty"`
+	Location    string `json:"location"` // "Group > Subgroup"
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// Image is a diagram whose image file is missing, or that has none recorded.
+type Image struct {
+	DiagramID  string `json:"diagram_id"`
+	SubgroupID string `json:"subgroup_id,omitempty"`
+	Path       string `json:"path"` // "" if no image is recorded
+}
+
+// Tag is a tag and how many parts have it.
+type Tag struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Parts    int    `json:"parts"`
+}
+
+// Scrape sums up the scraper's progress.
+type Scrape struct {
+	Completed int       `json:"completed"`
+	Failed    int       `json:"failed"`
+	Pending   int       `json:"pending"`
+	Failures  []Failure `json:"failures"`
+}
+
+// Failure is a page the scraper couldn't read, and why.
+type Failure struct {
+	URL       string `json:"url"`
+	Error     string `json:"error,omitempty"`
+	ScrapedAt string `json:"scraped_at,omitempty"`
+}
+
+// Build gathers the report, checking each diagram's image under dataPath
+func Build(database *db.DB, dataPath string) (*Report, error) {
+	r := &Report{}
+
+	counts, err := database.GetCatalogCounts()
+	if err != nil {
+		return nil, err
+	}
+	r.Totals = Totals{
+		Groups:      counts.Groups,
+		Subgroups:   counts.Subgroups,
+		Diagrams:    counts.Diagrams,
+		Parts:       counts.Parts,
+		TaggedParts: counts.TaggedParts,
+	}
+
+	subgroups, err := database.GetSubgroupCounts()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range subgroups {
+		if len(r.Groups) == 0 || r.Groups[len(r.Groups)-1].ID != s.GroupID {
+			r.Groups = append(r.Groups, Group{ID: s.GroupID, Name: s.GroupName})
+		}
+		if s.SubgroupID == "" {
+			continue
+		}
+		g := &r.Groups[len(r.Groups)-1]
+		g.Parts += s.Parts
+		g.Diagrams += s.Diagrams
+		g.Subgroups = append(g.Subgroups, Subgroup{ID: s.SubgroupID, Name: s.SubgroupName, Parts: s.Parts, Diagrams: s.Diagrams})
+		if s.Parts == 0 {
+			r.Totals.EmptySubgroups++
+		}
+	}
+
+	noDiagram, err := database.GetPartsWithoutDiagrams()
+	if err != nil {
+		return nil, err
+	}
+	r.NoDiagram = parts(noDiagram)
+
+	replaced, err := database.GetReplacedParts()
+	if err != nil {
+		return nil, err
+	}
+	r.Replaced = parts(replaced)
+
+	images, err := database.GetDiagramImages()
+	if err != nil {
+		return nil, err
+	}
+	for _, img := range images {
+		if img.Path != "" {
+			if _, err := os.Stat(filepath.Join(dataPath, img.Path)); err == nil {
+				continue
+			}
+		}
+		missing := Image{DiagramID: img.DiagramID, Path: img.Path}
+		if img.SubgroupID != nil {
+			missing.SubgroupID = *img.SubgroupID
+		}
+		r.MissingImages = append(r.MissingImages, missing)
+	}
+
+	tags, err := database.GetTagCounts()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tags {
+		r.Tags = append(r.Tags, Tag{ID: t.ID, Name: t.Name, Category: t.Category, Parts: t.Parts})
+	}
+
+	progress, err := database.GetScrapeProgress()
+	if err != nil {
+		return nil, err
+	}
+	if progress != nil {
+		r.Scrape = &Scrape{Completed: progress.Completed, Failed: progress.Failed, Pending: progress.Pending}
+		for _, f := range progress.Failures {
+			r.Scrape.Failures = append(r.Scrape.Failures, Failure{URL: f.URL, Error: deref(f.Error), ScrapedAt: deref(f.ScrapedAt)})
+		}
+	}
+	return r, nil
+}
+
+// parts converts the database's part references for the report
+func parts(refs []db.PartRef) []Part {
+	var list []Part
+	for _, p := range refs {
+		location := p.GroupName
+		if p.SubgroupName != nil {
+			location += " > " + *p.SubgroupName
+		}
+		list = append(list, Part{
+			ID:          p.ID,
+			PartNumber:  p.PartNumber,
+			Description: deref(p.Description),
+			Location:    location,
+			Replacement: deref(p.Replacement),
+		})
+	}
+	return list
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// TagCoverage is the share of parts with at least one tag, in percent
+func (t Totals) TagCoverage() int {
+	if t.Parts == 0 {
+		return 0
+	}
+	return t.TaggedParts * 100 / t.Parts
+}
+
+// Complete reports whether every page was scraped
+func (s *Scrape) Complete() bool {
+	return s.Failed == 0 && s.Pending == 0
+}
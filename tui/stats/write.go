@@ -0,0 +1,108 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Write writes the report as plain text or JSON
+func Write(w io.Writer, r *Report, format string) error {
+	switch format {
+	case "text":
+		return writeText(w, r)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+	return fmt.Errorf("unknown format %q (want %s)", format, strings.Join(Formats, ", "))
+}
+
+// writeText writes the report as aligned sections, summary first
+func writeText(w io.Writer, r *Report) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	line := func(format string, args ...any) {
+		fmt.Fprintf(tw, format+"\n", args...)
+	}
+
+	t := r.Totals
+	line("CATALOG")
+	line("  Groups\t%d", t.Groups)
+	line("  Subgroups\t%d\t%d without parts", t.Subgroups, t.EmptySubgroups)
+	line("  Diagrams\t%d\t%s", t.Diagrams, count(len(r.MissingImages), "missing image"))
+	line("  Parts\t%d\t%d without diagrams, %d replaced", t.Parts, len(r.NoDiagram), len(r.Replaced))
+	line("  Tagged parts\t%d\t%d%% of parts", t.TaggedParts, t.TagCoverage())
+	line("")
+
+	line("SCRAPE PROGRESS")
+	if s := r.Scrape; s == nil {
+		line("  No scrape_progress table in this database")
+	} else {
+		line("  Completed\t%d", s.Completed)
+		line("  Failed\t%d", s.Failed)
+		line("  Pending\t%d", s.Pending)
+		if len(s.Failures) > 0 {
+			line("  Failed pages:")
+		}
+		for _, f := range s.Failures {
+			line("    %s\t%s", f.URL, f.Error)
+		}
+	}
+	line("")
+
+	line("SUBGROUPS")
+	for _, g := range r.Groups {
+		line("  %s\t%s\t%s", g.Name, count(g.Parts, "part"), count(g.Diagrams, "diagram"))
+		for _, s := range g.Subgroups {
+			note := ""
+			if s.Parts == 0 {
+				note = "\tempty"
+			}
+			line("    %s\t%s\t%s%s", s.Name, count(s.Parts, "part"), count(s.Diagrams, "diagram"), note)
+		}
+	}
+	line("")
+
+	section := func(title string, n int) {
+		line("%s (%d)", title, n)
+	}
+	section("PARTS WITHOUT DIAGRAMS", len(r.NoDiagram))
+	for _, p := range r.NoDiagram {
+		line("  %s\t%s\t%s", p.PartNumber, p.Description, p.Location)
+	}
+	line("")
+
+	section("DIAGRAMS WITH MISSING IMAGES", len(r.MissingImages))
+	for _, img := range r.MissingImages {
+		path := img.Path
+		if path == "" {
+			path = "no image recorded"
+		}
+		line("  %s\t%s\t%s", img.DiagramID, path, img.SubgroupID)
+	}
+	line("")
+
+	section("PARTS WITH REPLACEMENTS", len(r.Replaced))
+	for _, p := range r.Replaced {
+		line("  %s\t→ %s\t%s", p.PartNumber, p.Replacement, p.Location)
+	}
+	line("")
+
+	section("TAGS", len(r.Tags))
+	for _, tag := range r.Tags {
+		line("  %s\t%s\t%s", tag.Category, tag.Name, count(tag.Parts, "part"))
+	}
+
+	return tw.Flush()
+}
+
+// count writes n with a noun, plural unless n is 1
+func count(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
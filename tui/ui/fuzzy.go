@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+)
+
+// FuzzyMatch matches each word of the query, in order, against the letters
+// of text, so "cyl hd" finds "Cylinder Head". Letters that start a word or
+// follow the previous match score higher, as do shorter texts. It returns
+// the positions matched among text's own runes, and false if any word
+// doesn't match. An empty query matches everything equally.
+func FuzzyMatch(query, text string) (int, []int, bool) {
+	words := strings.Fields(strings.ToLower(query))
+	if len(words) == 0 {
+		return 0, nil, true
+	}
+
+	// Fold case rune by rune, so positions index text as given
+	runes := []rune(text)
+	var positions []int
+	score, pos := 0, 0
+	for _, word := range words {
+		last := -2
+		for _, q := range word {
+			i := pos
+			for i < len(runes) && unicode.ToLower(runes[i]) != q {
+				i++
+			}
+			if i == len(runes) {
+				return 0, nil, false
+			}
+			score++
+			switch {
+			case i == last+1:
+				score += 3
+			case i == 0 || !unicode.IsLetter(runes[i-1]) && !unicode.IsDigit(runes[i-1]):
+				score += 5
+			}
+			positions = append(positions, i)
+			last, pos = i, i+1
+		}
+	}
+	return score*100 - len(runes), positions, true
+}
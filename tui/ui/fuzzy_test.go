@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		query, text string
+		positions   []int
+		ok          bool
+	}{
+		{"", "Cylinder Head", nil, true},
+		{"cyl", "Cylinder Head", []int{0, 1, 2}, true},
+		{"cyl hd", "Cylinder Head", []int{0, 1, 2, 9, 12}, true},
+		{"CYL", "cylinder head", []int{0, 1, 2}, true},
+		{"hd cyl", "Cylinder Head", nil, false}, // Words match in order
+		{"xyz", "Cylinder Head", nil, false},
+		{"wp", "WATER PUMP", []int{0, 6}, true},
+		{"öl", "ÖLFILTER", []int{0, 1}, true}, // Positions count runes
+		{"filter", "ÖLFILTER", []int{2, 3, 4, 5, 6, 7}, true},
+	}
+	for _, tt := range tests {
+		_, positions, ok := FuzzyMatch(tt.query, tt.text)
+		if ok != tt.ok || !slices.Equal(positions, tt.positions) {
+			t.Errorf("FuzzyMatch(%q, %q) = %v, %v, want %v, %v", tt.query, tt.text, positions, ok, tt.positions, tt.ok)
+		}
+	}
+}
+
+func TestFuzzyMatchScore(t *testing.T) {
+	// Each pair lists the better match first
+	tests := []struct {
+		query         string
+		better, worse string
+	}{
+		{"head", "Head", "Cylinder Head"},     // Shorter text
+		{"hd", "Head Gasket", "Shaded"},       // Word starts
+		{"gas", "Gasket", "Gear Assembly"},    // Consecutive letters
+		{"pump", "Water Pump", "Pull Up Map"}, // Consecutive beats scattered
+	}
+	for _, tt := range tests {
+		better, _, ok1 := FuzzyMatch(tt.query, tt.better)
+		worse, _, ok2 := FuzzyMatch(tt.query, tt.worse)
+		if !ok1 || !ok2 {
+			t.Errorf("FuzzyMatch(%q): %q or %q didn't match", tt.query, tt.better, tt.worse)
+			continue
+		}
+		if better <= worse {
+			t.Errorf("FuzzyMatch(%q): %q scored %d, not above %q at %d", tt.query, tt.better, better, tt.worse, worse)
+		}
+	}
+}
+
+func TestMenuFilter(t *testing.T) {
+	items := []MenuItem{
+		{ID: "a", Label: "Alternator", Hint: "electrical"},
+		{ID: "b", Label: "Water pump", Hint: "cooling"},
+		{ID: "c", Label: "Cylinder head", Hint: "engine"},
+	}
+	tests := []struct {
+		query  string
+		shown  []int
+		cursor int
+	}{
+		{"", []int{0, 1, 2}, 0},
+		{"pump", []int{1}, 1},
+		{"cool", []int{1}, 1}, // Hints match too
+		{"hd", []int{2}, 2},
+		{"zz", []int{}, 0},
+	}
+	for _, tt := range tests {
+		m := NewMenu(items)
+		m.setQuery(tt.query)
+		if got := m.visible(); !slices.Equal(got, tt.shown) {
+			t.Errorf("query %q shows %v, want %v", tt.query, got, tt.shown)
+		}
+		if m.Cursor != tt.cursor {
+			t.Errorf("query %q puts the cursor on %d, want %d", tt.query, m.Cursor, tt.cursor)
+		}
+		m.View()
+		if m.Cursor != tt.cursor {
+			t.Errorf("query %q: View moved the cursor to %d", tt.query, m.Cursor)
+		}
+	}
+}
+
+func TestMenuSetCursorSkipsHidden(t *testing.T) {
+	m := NewMenu([]MenuItem{{ID: "a", Label: "Alternator"}, {ID: "b", Label: "Water pump"}})
+	m.setQuery("pump")
+	m.SetCursor(0)
+	if m.Cursor != 1 {
+		t.Errorf("SetCursor(0) with it hidden = %d, want 1", m.Cursor)
+	}
+	if m.SelectID("a") {
+		t.Errorf("SelectID found a hidden item")
+	}
+	m.SetCursor(5)
+	if m.Cursor != 1 {
+		t.Errorf("SetCursor(5) = %d, want 1", m.Cursor)
+	}
+}
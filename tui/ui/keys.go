@@ -37,3 +37,147 @@ func IsNote(msg tea.KeyMsg) bool {
 func IsSaveNote(msg tea.KeyMsg) bool {
 	return msg.Type == tea.KeyCtrlS
 }
+
+func IsGrowPane(msg tea.KeyMsg) bool {
+	return msg.String() == ">"
+}
+
+func IsShrinkPane(msg tea.KeyMsg) bool {
+	return msg.String() == "<"
+}
+
+func IsCycleOrientation(msg tea.KeyMsg) bool {
+	return msg.String() == "\\"
+}
+
+func IsCyclePane(msg tea.KeyMsg) bool {
+	return msg.String() == "|"
+}
+
+func IsZoom(msg tea.KeyMsg) bool {
+	return msg.String() == "z"
+}
+
+func IsTab(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyTab
+}
+
+func IsBackTab(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyShiftTab
+}
+
+func IsToggle(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeySpace
+}
+
+func IsNextPage(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyPgDown
+}
+
+func IsPrevPage(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyPgUp
+}
+
+func IsSaveSearch(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyCtrlS
+}
+
+func IsDelete(msg tea.KeyMsg) bool {
+	return msg.String() == "x"
+}
+
+func IsExternalEdit(msg tea.KeyMsg) bool {
+	return msg.String() == "e"
+}
+
+func IsAttach(msg tea.KeyMsg) bool {
+	return msg.String() == "a"
+}
+
+func IsHistoryBack(msg tea.KeyMsg) bool {
+	return msg.String() == "alt+left"
+}
+
+func IsHistoryForward(msg tea.KeyMsg) bool {
+	return msg.String() == "alt+right"
+}
+
+func IsJumpList(msg tea.KeyMsg) bool {
+	return msg.String() == "H"
+}
+
+func IsBreadcrumbs(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyCtrlB
+}
+
+func IsLeft(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyLeft || msg.String() == "h"
+}
+
+func IsRight(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyRight || msg.String() == "l"
+}
+
+func IsCopyPartNumber(msg tea.KeyMsg) bool {
+	return msg.String() == "y"
+}
+
+func IsCopyRow(msg tea.KeyMsg) bool {
+	return msg.String() == "Y"
+}
+
+func IsCopySummary(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyCtrlY
+}
+
+func IsMark(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyCtrlT
+}
+
+func IsMarkUp(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyShiftUp
+}
+
+func IsMarkDown(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyShiftDown
+}
+
+func IsMarkAll(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyCtrlA
+}
+
+func IsBatchActions(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyCtrlX
+}
+
+func IsExport(msg tea.KeyMsg) bool {
+	return msg.String() == "e"
+}
+
+func IsCycleFit(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyCtrlF
+}
+
+func IsCycleTheme(msg tea.KeyMsg) bool {
+	return msg.String() == "T"
+}
+
+func IsPalette(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyCtrlP
+}
+
+func IsFilter(msg tea.KeyMsg) bool {
+	return msg.String() == "f"
+}
+
+func IsContactSheet(msg tea.KeyMsg) bool {
+	return msg.String() == "g"
+}
+
+func IsNextSubgroup(msg tea.KeyMsg) bool {
+	return msg.String() == "]"
+}
+
+func IsPrevSubgroup(msg tea.KeyMsg) bool {
+	return msg.String() == "["
+}
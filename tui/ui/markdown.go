@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/styles"
+)
+
+// RenderMarkdown formats Markdown text for the terminal, wrapped to width.
+// Text that fails to render is returned unchanged.
+func RenderMarkdown(text string, width int) string {
+	// Drop the document margins so the text lines up with surrounding content
+	style := styles.DarkStyleConfig
+	switch current.Markdown {
+	case "light":
+		style = styles.LightStyleConfig
+	case "notty":
+		style = styles.NoTTYStyleConfig
+	}
+	margin := uint(0)
+	style.Document.Margin = &margin
+	style.Document.BlockPrefix = ""
+	style.Document.BlockSuffix = ""
+
+	r, err := glamour.NewTermRenderer(
+		glamour.WithStyles(style),
+		glamour.WithWordWrap(width),
+		glamour.WithPreservedNewLines(),
+	)
+	if err != nil {
+		return text
+	}
+	out, err := r.Render(text)
+	if err != nil {
+		return text
+	}
+
+	lines := strings.Split(strings.Trim(out, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
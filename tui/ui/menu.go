@@ -2,19 +2,39 @@ package ui
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 type MenuItem struct {
-	ID    string
-	Label string
-	Hint  string
+	ID     string
+	Label  string
+	Hint   string
+	Dim    bool // Draw the label greyed out, e.g. for parts that don't fit
+	Accent bool // Draw the label highlighted, e.g. for parts in the van's color
 }
 
 type Menu struct {
 	Items           []MenuItem
 	Cursor          int
 	MaxVisibleItems int
+	LabelWidth      int  // Pad labels to this width so hints line up (0 = no padding)
+	KeepCase        bool // Draw labels and hints as given instead of in capitals, e.g. for URLs
+
+	// IDs of items picked for a batch action
+	marked map[string]bool
+
+	// Type-to-filter query hiding the items that don't match it, and
+	// whether it is still being typed
+	query     string
+	filtering bool
+
+	// Hit-testing information recorded by the last render
+	origin Rect  // Screen position of the menu's first line
+	rows   []int // Item index for each rendered line (-1 for indicators and padding)
 }
 
 func NewMenu(items []MenuItem) *Menu {
@@ -26,53 +46,381 @@ func NewMenu(items []MenuItem) *Menu {
 }
 
 func (m *Menu) Up() {
-	if m.Cursor > 0 {
-		m.Cursor--
+	for i := m.Cursor - 1; i >= 0; i-- {
+		if m.Shown(i) {
+			m.Cursor = i
+			return
+		}
 	}
 }
 
 func (m *Menu) Down() {
-	if m.Cursor < len(m.Items)-1 {
-		m.Cursor++
+	for i := m.Cursor + 1; i < len(m.Items); i++ {
+		if m.Shown(i) {
+			m.Cursor = i
+			return
+		}
 	}
 }
 
+// SetCursor moves the cursor to item i, clamped to the menu
+func (m *Menu) SetCursor(i int) {
+	m.Cursor = i
+	m.clampCursor()
+}
+
+// clampCursor keeps the cursor within the menu, and on an item the filter
+// shows if there are any
+func (m *Menu) clampCursor() {
+	m.Cursor = max(min(m.Cursor, len(m.Items)-1), 0)
+	if len(m.Items) > 0 && !m.Shown(m.Cursor) {
+		if shown := m.visible(); len(shown) > 0 {
+			m.Cursor = shown[0]
+		}
+	}
+}
+
+// SelectID moves the cursor to the item with an ID, reporting whether
+// there is one
+func (m *Menu) SelectID(id string) bool {
+	if id == "" {
+		return false
+	}
+	for i, item := range m.Items {
+		if item.ID == id && m.Shown(i) {
+			m.Cursor = i
+			return true
+		}
+	}
+	return false
+}
+
+// Selected returns the item under the cursor, or nil if there is none or
+// the filter hides it
 func (m *Menu) Selected() *MenuItem {
-	if m.Cursor >= 0 && m.Cursor < len(m.Items) {
+	if m.Cursor >= 0 && m.Cursor < len(m.Items) && m.Shown(m.Cursor) {
 		return &m.Items[m.Cursor]
 	}
 	return nil
 }
 
+// ToggleMark picks or unpicks the item under the cursor
+func (m *Menu) ToggleMark() {
+	if item := m.Selected(); item != nil {
+		m.setMark(item.ID, !m.marked[item.ID])
+	}
+}
+
+// Mark picks the item under the cursor
+func (m *Menu) Mark() {
+	if item := m.Selected(); item != nil {
+		m.setMark(item.ID, true)
+	}
+}
+
+// MarkAll picks every item the filter shows, or unpicks them if they all
+// already are. Picks on items the filter hides are kept.
+func (m *Menu) MarkAll() {
+	shown := m.visible()
+	all := true
+	for _, i := range shown {
+		if !m.marked[m.Items[i].ID] {
+			all = false
+			break
+		}
+	}
+	for _, i := range shown {
+		m.setMark(m.Items[i].ID, !all)
+	}
+}
+
+func (m *Menu) ClearMarks() {
+	m.marked = nil
+}
+
+func (m *Menu) setMark(id string, on bool) {
+	if m.marked == nil {
+		m.marked = make(map[string]bool)
+	}
+	if on {
+		m.marked[id] = true
+	} else {
+		delete(m.marked, id)
+	}
+}
+
+func (m *Menu) MarkedCount() int {
+	return len(m.marked)
+}
+
+// Marked returns the picked items in menu order
+func (m *Menu) Marked() []MenuItem {
+	var items []MenuItem
+	for _, item := range m.Items {
+		if m.marked[item.ID] {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// SetOrigin records where the menu will be drawn on screen so mouse
+// events can be mapped back to items. Call it before View.
+func (m *Menu) SetOrigin(x, y, width int) {
+	m.origin = Rect{X: x, Y: y, Width: width, Height: m.MaxVisibleItems}
+}
+
+// ItemAt returns the index of the item drawn at screen position x, y, or -1.
+func (m *Menu) ItemAt(x, y int) int {
+	if !m.origin.Contains(x, y) {
+		return -1
+	}
+	row := y - m.origin.Y
+	if row >= len(m.rows) {
+		return -1
+	}
+	return m.rows[row]
+}
+
+// Mouse applies a mouse event to the menu. The wheel moves the cursor and a
+// left click moves it to the item under the pointer. It returns true when an
+// item was clicked, which callers treat like enter.
+func (m *Menu) Mouse(msg tea.MouseMsg) bool {
+	if !m.origin.Contains(msg.X, msg.Y) {
+		return false
+	}
+	switch {
+	case IsWheelUp(msg):
+		m.Up()
+	case IsWheelDown(msg):
+		m.Down()
+	case IsClick(msg):
+		if i := m.ItemAt(msg.X, msg.Y); i >= 0 {
+			m.Cursor = i
+			return true
+		}
+	}
+	return false
+}
+
+// StartFilter begins typing a filter query
+func (m *Menu) StartFilter() {
+	m.filtering = true
+}
+
+// Filtering reports whether a filter query is being typed
+func (m *Menu) Filtering() bool {
+	return m.filtering
+}
+
+// Filtered reports whether a filter query is being typed or hides items
+func (m *Menu) Filtered() bool {
+	return m.filtering || m.query != ""
+}
+
+// ClearFilter drops the filter query, showing every item again
+func (m *Menu) ClearFilter() {
+	m.query, m.filtering = "", false
+}
+
+// UpdateFilter applies a key typed into the filter query: text, backspace,
+// and esc to clear it. Other keys, such as the arrows, are left to the
+// screen and reported as not handled; enter also finishes typing, so that
+// it opens the selected item.
+func (m *Menu) UpdateFilter(msg tea.KeyMsg) bool {
+	switch msg.Type {
+	case tea.KeyRunes, tea.KeySpace:
+		m.setQuery(m.query + string(msg.Runes))
+	case tea.KeyBackspace:
+		if m.query == "" {
+			m.filtering = false
+		} else {
+			q := []rune(m.query)
+			m.setQuery(string(q[:len(q)-1]))
+		}
+	case tea.KeyEscape:
+		m.ClearFilter()
+	case tea.KeyEnter:
+		m.filtering = false
+		return false
+	default:
+		return false
+	}
+	return true
+}
+
+// setQuery changes the filter query and moves the cursor to the best match
+func (m *Menu) setQuery(query string) {
+	m.query = query
+	best := -1
+	bestScore := 0
+	for i, item := range m.Items {
+		if score, ok := m.match(item); ok && (best < 0 || score > bestScore) {
+			best, bestScore = i, score
+		}
+	}
+	if best >= 0 {
+		m.Cursor = best
+	}
+}
+
+// match tests an item against the filter query: its label, or failing
+// that its hint, each as drawn. It returns the score.
+func (m *Menu) match(item MenuItem) (score int, ok bool) {
+	if score, _, ok = FuzzyMatch(m.query, m.drawn(item.Label)); ok {
+		return score, true
+	}
+	if item.Hint == "" {
+		return 0, false
+	}
+	score, _, ok = FuzzyMatch(m.query, m.drawn(item.Hint))
+	// A label match beats a hint match
+	return score - 10000, ok
+}
+
+// drawn returns text as the menu draws it: in capitals unless KeepCase is set
+func (m *Menu) drawn(text string) string {
+	if m.KeepCase {
+		return text
+	}
+	return strings.ToUpper(text)
+}
+
+// Shown reports whether item i passes the filter
+func (m *Menu) Shown(i int) bool {
+	if m.query == "" {
+		return true
+	}
+	_, ok := m.match(m.Items[i])
+	return ok
+}
+
+// visible lists the indexes of the items the filter shows
+func (m *Menu) visible() []int {
+	shown := make([]int, 0, len(m.Items))
+	for i := range m.Items {
+		if m.Shown(i) {
+			shown = append(shown, i)
+		}
+	}
+	return shown
+}
+
+// FilterLine describes the filter for the line above the items, or returns
+// "" when there is none
+func (m *Menu) FilterLine() string {
+	if !m.Filtered() {
+		return ""
+	}
+	line := SelectedStyle.Render("f ") + NormalLabelStyle.Render(m.query)
+	if m.filtering {
+		line += SelectedStyle.Render("▏")
+	}
+	shown := len(m.visible())
+	if shown == 0 {
+		return line + DimStyle.Render("  no matches")
+	}
+	return line + DimStyle.Render(fmt.Sprintf("  %d of %d", shown, len(m.Items)))
+}
+
+// RenderLabel draws item i's label with a style, in upper case unless
+// KeepCase is set, underlining the letters the filter matched
+func (m *Menu) RenderLabel(i int, style lipgloss.Style) string {
+	label := m.drawn(m.Items[i].Label)
+	if m.query == "" {
+		return style.Render(label)
+	}
+	_, positions, _ := FuzzyMatch(m.query, label)
+	return highlight(label, positions, style)
+}
+
+// RenderHint draws item i's hint dimmed, underlining the letters the filter
+// matched if the label didn't match
+func (m *Menu) RenderHint(i int) string {
+	return m.renderHint(i, m.Items[i].Hint)
+}
+
+// renderHint draws a hint for item i, such as the upper case one menus
+// show, underlining the letters the filter matched in it
+func (m *Menu) renderHint(i int, hint string) string {
+	if m.query == "" {
+		return DimStyle.Render(hint)
+	}
+	if _, _, ok := FuzzyMatch(m.query, m.drawn(m.Items[i].Label)); ok {
+		return DimStyle.Render(hint)
+	}
+	_, positions, _ := FuzzyMatch(m.query, hint)
+	return highlight(hint, positions, DimStyle)
+}
+
+// highlight draws s with a style, underlining the runes at positions
+func highlight(s string, positions []int, style lipgloss.Style) string {
+	if len(positions) == 0 {
+		return style.Render(s)
+	}
+	marked := style.Underline(true)
+
+	var b strings.Builder
+	runes := []rune(s)
+	start := 0
+	for start < len(runes) {
+		on := slices.Contains(positions, start)
+		end := start + 1
+		for end < len(runes) && slices.Contains(positions, end) == on {
+			end++
+		}
+		if on {
+			b.WriteString(marked.Render(string(runes[start:end])))
+		} else {
+			b.WriteString(style.Render(string(runes[start:end])))
+		}
+		start = end
+	}
+	return b.String()
+}
+
 func (m *Menu) View() string {
+	m.rows = m.rows[:0]
+
 	if len(m.Items) == 0 {
 		return DimStyle.Render("No items")
 	}
 
+	// The filter takes the first line, and hides the items that don't match
+	var lines []string
+	maxVisible := m.MaxVisibleItems
+	if line := m.FilterLine(); line != "" {
+		lines = append(lines, line)
+		m.rows = append(m.rows, -1)
+		maxVisible--
+	}
+	shown := m.visible()
+	cursor := max(slices.Index(shown, m.Cursor), 0)
+
 	// Calculate visible window
 	// Reserve 2 lines for scroll indicators if needed
-	needsScrolling := len(m.Items) > m.MaxVisibleItems
-	visibleItems := m.MaxVisibleItems
+	needsScrolling := len(shown) > maxVisible
+	visibleItems := maxVisible
 	if needsScrolling {
-		visibleItems = m.MaxVisibleItems - 2 // Reserve space for ↑/↓ indicators
+		visibleItems = maxVisible - 2 // Reserve space for ↑/↓ indicators
 	}
 	if visibleItems < 1 {
 		visibleItems = 1
 	}
 
 	windowStart := 0
-	windowEnd := len(m.Items)
+	windowEnd := len(shown)
 
 	if needsScrolling {
 		// Keep cursor in the middle of the visible area when possible
 		halfVisible := visibleItems / 2
-		windowStart = m.Cursor - halfVisible
+		windowStart = cursor - halfVisible
 		if windowStart < 0 {
 			windowStart = 0
 		}
 		windowEnd = windowStart + visibleItems
-		if windowEnd > len(m.Items) {
-			windowEnd = len(m.Items)
+		if windowEnd > len(shown) {
+			windowEnd = len(shown)
 			windowStart = windowEnd - visibleItems
 			if windowStart < 0 {
 				windowStart = 0
@@ -81,10 +429,9 @@ func (m *Menu) View() string {
 	}
 
 	hasMoreAbove := windowStart > 0
-	hasMoreBelow := windowEnd < len(m.Items)
+	hasMoreBelow := windowEnd < len(shown)
 
 	// Build output with fixed number of lines (always MaxVisibleItems)
-	var lines []string
 
 	// Line 1: "more above" indicator (if scrollable)
 	if needsScrolling {
@@ -93,34 +440,55 @@ func (m *Menu) View() string {
 		} else {
 			lines = append(lines, "")
 		}
+		m.rows = append(m.rows, -1)
 	}
 
 	// Menu items
-	for i := windowStart; i < windowEnd; i++ {
+	for _, i := range shown[windowStart:windowEnd] {
 		item := m.Items[i]
 		isSelected := i == m.Cursor
 
-		var line string
+		// Check column, shown while any items are picked
+		check := ""
+		if len(m.marked) > 0 {
+			check = "  "
+			if m.marked[item.ID] {
+				check = SelectedStyle.Render("✓ ")
+			}
+		}
+
+		style := NormalLabelStyle
+		prefix := "  "
 		if isSelected {
-			line = SelectedStyle.Render("› ") + SelectedLabelStyle.Render(strings.ToUpper(item.Label))
-		} else {
-			line = "  " + NormalLabelStyle.Render(strings.ToUpper(item.Label))
+			style = SelectedLabelStyle
+			prefix = SelectedStyle.Render("› ")
+		} else if item.Dim {
+			style = DimStyle
+		} else if item.Accent {
+			style = MatchStyle
+		}
+		label := m.RenderLabel(i, style)
+		if pad := m.LabelWidth - lipgloss.Width(label); pad > 0 {
+			label += style.Render(strings.Repeat(" ", pad))
 		}
+		line := prefix + check + label
 
 		if item.Hint != "" {
-			line += DimStyle.Render(" " + strings.ToUpper(item.Hint))
+			line += DimStyle.Render(" ") + m.renderHint(i, m.drawn(item.Hint))
 		}
 
 		lines = append(lines, line)
+		m.rows = append(m.rows, i)
 	}
 
 	// Last line: "more below" indicator (if scrollable)
 	if needsScrolling {
 		if hasMoreBelow {
-			lines = append(lines, DimStyle.Render(fmt.Sprintf("  ↓ %d more", len(m.Items)-windowEnd)))
+			lines = append(lines, DimStyle.Render(fmt.Sprintf("  ↓ %d more", len(shown)-windowEnd)))
 		} else {
 			lines = append(lines, "")
 		}
+		m.rows = append(m.rows, -1)
 	}
 
 	// Pad to MaxVisibleItems if we have fewer lines
@@ -0,0 +1,15 @@
+package ui
+
+import tea "github.com/charmbracelet/bubbletea"
+
+func IsClick(msg tea.MouseMsg) bool {
+	return msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress
+}
+
+func IsWheelUp(msg tea.MouseMsg) bool {
+	return msg.Button == tea.MouseButtonWheelUp
+}
+
+func IsWheelDown(msg tea.MouseMsg) bool {
+	return msg.Button == tea.MouseButtonWheelDown
+}
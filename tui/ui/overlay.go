@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Overlay draws fg over bg with its top left corner at column x, row y.
+// The background shows on either side of each overlaid line.
+func Overlay(bg, fg string, x, y int) string {
+	bgLines := strings.Split(bg, "\n")
+	for i, line := range strings.Split(fg, "\n") {
+		row := y + i
+		if row < 0 {
+			continue
+		}
+		for row >= len(bgLines) {
+			bgLines = append(bgLines, "")
+		}
+
+		under := bgLines[row]
+		left := ansi.Truncate(under, x, "")
+		if w := lipgloss.Width(left); w < x {
+			left += strings.Repeat(" ", x-w)
+		}
+		right := ansi.TruncateLeft(under, x+lipgloss.Width(line), "")
+		bgLines[row] = left + "\x1b[0m" + line + "\x1b[0m" + right
+	}
+	return strings.Join(bgLines, "\n")
+}
+
+// kittyImage matches a Kitty graphics protocol escape sequence
+var kittyImage = regexp.MustCompile("\x1b_G[^\x1b]*\x1b\\\\")
+
+// StripImages removes images from rendered output, so that text can be
+// drawn where they would be
+func StripImages(s string) string {
+	return kittyImage.ReplaceAllString(s, "")
+}
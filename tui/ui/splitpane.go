@@ -6,12 +6,167 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// Orientation controls how the two panes of a split are arranged.
+type Orientation string
+
+const (
+	OrientationAuto       Orientation = ""             // Side by side, stacked below StackWidth
+	OrientationSideBySide Orientation = "side-by-side" // Left pane beside right pane
+	OrientationStacked    Orientation = "stacked"      // Left pane above right pane
+)
+
+// Pane identifies one side of a split pane.
+type Pane string
+
+const (
+	PaneNone  Pane = ""
+	PaneLeft  Pane = "left"
+	PaneRight Pane = "right"
+)
+
+const (
+	DefaultSplitRatio = 40
+	MinSplitRatio     = 20
+	MaxSplitRatio     = 80
+	splitRatioStep    = 5
+
+	// StackWidth is the terminal width below which auto layouts stack vertically
+	StackWidth = 100
+
+	splitLeftMargin = 2 // Left margin for the whole split pane
+)
+
+// SplitLayout describes how a split pane divides the screen.
+type SplitLayout struct {
+	Ratio       int         // Percentage of space given to the left (or top) pane
+	Orientation Orientation // Side by side, stacked, or chosen by width
+	Hidden      Pane        // Pane hidden from view, if any
+}
+
+// Rect is a region of the split pane in terminal cells, relative to its top-left corner.
+type Rect struct {
+	X, Y          int
+	Width, Height int
+}
+
+// Contains reports whether the cell at x, y falls inside the rect.
+func (r Rect) Contains(x, y int) bool {
+	return x >= r.X && x < r.X+r.Width && y >= r.Y && y < r.Y+r.Height
+}
+
+// DefaultSplitLayout returns the original 40/60 side-by-side layout.
+func DefaultSplitLayout() SplitLayout {
+	return SplitLayout{Ratio: DefaultSplitRatio}
+}
+
+// Grow gives more space to the left (or top) pane.
+func (l *SplitLayout) Grow() {
+	l.Ratio = clampRatio(l.ratio() + splitRatioStep)
+}
+
+// Shrink gives more space to the right (or bottom) pane.
+func (l *SplitLayout) Shrink() {
+	l.Ratio = clampRatio(l.ratio() - splitRatioStep)
+}
+
+// CycleOrientation steps through auto, side-by-side and stacked.
+func (l *SplitLayout) CycleOrientation() {
+	switch l.Orientation {
+	case OrientationAuto:
+		l.Orientation = OrientationSideBySide
+	case OrientationSideBySide:
+		l.Orientation = OrientationStacked
+	default:
+		l.Orientation = OrientationAuto
+	}
+}
+
+// CycleHidden steps through showing both panes, hiding the left, and hiding the right.
+func (l *SplitLayout) CycleHidden() {
+	switch l.Hidden {
+	case PaneNone:
+		l.Hidden = PaneLeft
+	case PaneLeft:
+		l.Hidden = PaneRight
+	default:
+		l.Hidden = PaneNone
+	}
+}
+
+// Stacked reports whether the panes are arranged vertically at the given width.
+func (l SplitLayout) Stacked(totalWidth int) bool {
+	switch l.Orientation {
+	case OrientationStacked:
+		return true
+	case OrientationSideBySide:
+		return false
+	}
+	return totalWidth < StackWidth
+}
+
+// Panes returns the content regions of the left and right panes.
+// A hidden pane has zero size.
+func (l SplitLayout) Panes(totalWidth, totalHeight int) (left, right Rect) {
+	innerWidth := totalWidth - splitLeftMargin
+
+	switch l.Hidden {
+	case PaneLeft:
+		return Rect{}, Rect{X: splitLeftMargin, Width: innerWidth, Height: totalHeight}
+	case PaneRight:
+		return Rect{X: splitLeftMargin, Width: innerWidth, Height: totalHeight}, Rect{}
+	}
+
+	if l.Stacked(totalWidth) {
+		topHeight := (totalHeight - 1) * l.ratio() / 100
+		if topHeight < 1 {
+			topHeight = 1
+		}
+		left = Rect{X: splitLeftMargin, Width: innerWidth, Height: topHeight}
+		right = Rect{X: splitLeftMargin, Y: topHeight + 1, Width: innerWidth, Height: totalHeight - topHeight - 1}
+		return left, right
+	}
+
+	leftWidth := innerWidth * l.ratio() / 100
+	rightWidth := innerWidth - leftWidth - 3 // Account for border
+	left = Rect{X: splitLeftMargin, Width: leftWidth, Height: totalHeight}
+	right = Rect{X: splitLeftMargin + leftWidth + 2, Width: rightWidth - 2, Height: totalHeight}
+	return left, right
+}
+
+func (l SplitLayout) ratio() int {
+	if l.Ratio == 0 {
+		return DefaultSplitRatio
+	}
+	return clampRatio(l.Ratio)
+}
+
+func clampRatio(ratio int) int {
+	if ratio < MinSplitRatio {
+		return MinSplitRatio
+	}
+	if ratio > MaxSplitRatio {
+		return MaxSplitRatio
+	}
+	return ratio
+}
+
 // RenderSplitPane renders a split pane with left and right content.
-func RenderSplitPane(left, right string, totalWidth, totalHeight int) string {
-	const leftMargin = 2 // Left margin for the whole split pane
+func RenderSplitPane(left, right string, totalWidth, totalHeight int, layout SplitLayout) string {
+	leftRect, rightRect := layout.Panes(totalWidth, totalHeight)
+	margin := strings.Repeat(" ", splitLeftMargin)
 
-	leftWidth := (totalWidth - leftMargin) * 40 / 100
-	rightWidth := totalWidth - leftMargin - leftWidth - 3 // Account for border
+	// A single visible pane takes the whole area
+	switch layout.Hidden {
+	case PaneLeft:
+		return renderPane(right, margin, rightRect)
+	case PaneRight:
+		return renderPane(left, margin, leftRect)
+	}
+
+	if layout.Stacked(totalWidth) {
+		rule := margin + DimStyle.Render(strings.Repeat("─", leftRect.Width))
+		return renderPane(left, margin, leftRect) + "\n" + rule + "\n" + renderPane(right, margin, rightRect)
+	}
 
 	// Fit content to exact height first
 	leftContent := FitHeight(left, totalHeight)
@@ -21,8 +176,6 @@ func RenderSplitPane(left, right string, totalWidth, totalHeight int) string {
 	leftLines := strings.Split(leftContent, "\n")
 	rightLines := strings.Split(rightContent, "\n")
 
-	margin := strings.Repeat(" ", leftMargin)
-
 	var result []string
 	for i := 0; i < totalHeight; i++ {
 		leftLine := ""
@@ -35,10 +188,10 @@ func RenderSplitPane(left, right string, totalWidth, totalHeight int) string {
 		}
 
 		// Pad left line to width
-		leftPadded := padToWidth(leftLine, leftWidth)
+		leftPadded := padToWidth(leftLine, leftRect.Width)
 
 		// Add border character and right content
-		rightPadded := "│ " + padToWidth(rightLine, rightWidth-2)
+		rightPadded := "│ " + padToWidth(rightLine, rightRect.Width)
 
 		result = append(result, margin+leftPadded+rightPadded)
 	}
@@ -46,6 +199,15 @@ func RenderSplitPane(left, right string, totalWidth, totalHeight int) string {
 	return strings.Join(result, "\n")
 }
 
+// renderPane renders a single pane's content fitted to its rect
+func renderPane(content, margin string, rect Rect) string {
+	lines := strings.Split(FitHeight(content, rect.Height), "\n")
+	for i, line := range lines {
+		lines[i] = margin + padToWidth(line, rect.Width)
+	}
+	return strings.Join(lines, "\n")
+}
+
 // padToWidth pads a string with spaces to reach the target width
 // Uses lipgloss width calculation to handle ANSI escape codes
 func padToWidth(s string, width int) string {
@@ -0,0 +1,79 @@
+package ui
+
+import "testing"
+
+func TestSplitLayoutPanes(t *testing.T) {
+	tests := []struct {
+		name          string
+		layout        SplitLayout
+		width, height int
+		left, right   Rect
+	}{
+		{"default", SplitLayout{}, 120, 30,
+			Rect{X: 2, Width: 47, Height: 30}, Rect{X: 51, Width: 66, Height: 30}},
+		{"wider left", SplitLayout{Ratio: 60}, 120, 30,
+			Rect{X: 2, Width: 70, Height: 30}, Rect{X: 74, Width: 43, Height: 30}},
+		{"ratio clamped", SplitLayout{Ratio: 95}, 120, 30,
+			Rect{X: 2, Width: 94, Height: 30}, Rect{X: 98, Width: 19, Height: 30}},
+		{"auto stacks when narrow", SplitLayout{}, 80, 30,
+			Rect{X: 2, Width: 78, Height: 11}, Rect{X: 2, Y: 12, Width: 78, Height: 18}},
+		{"stacked", SplitLayout{Orientation: OrientationStacked}, 120, 30,
+			Rect{X: 2, Width: 118, Height: 11}, Rect{X: 2, Y: 12, Width: 118, Height: 18}},
+		{"side by side when narrow", SplitLayout{Orientation: OrientationSideBySide}, 80, 30,
+			Rect{X: 2, Width: 31, Height: 30}, Rect{X: 35, Width: 42, Height: 30}},
+		{"stacked keeps a top row", SplitLayout{Orientation: OrientationStacked}, 80, 2,
+			Rect{X: 2, Width: 78, Height: 1}, Rect{X: 2, Y: 2, Width: 78, Height: 0}},
+		{"left hidden", SplitLayout{Hidden: PaneLeft}, 120, 30,
+			Rect{}, Rect{X: 2, Width: 118, Height: 30}},
+		{"right hidden", SplitLayout{Hidden: PaneRight, Orientation: OrientationStacked}, 120, 30,
+			Rect{X: 2, Width: 118, Height: 30}, Rect{}},
+	}
+	for _, tt := range tests {
+		left, right := tt.layout.Panes(tt.width, tt.height)
+		if left != tt.left || right != tt.right {
+			t.Errorf("%s: Panes(%d, %d) = %+v, %+v, want %+v, %+v",
+				tt.name, tt.width, tt.height, left, right, tt.left, tt.right)
+		}
+	}
+}
+
+func TestSplitLayoutResize(t *testing.T) {
+	tests := []struct {
+		name   string
+		ratio  int
+		change func(*SplitLayout)
+		want   int
+	}{
+		{"grow from default", 0, (*SplitLayout).Grow, 45},
+		{"shrink from default", 0, (*SplitLayout).Shrink, 35},
+		{"grow at the limit", MaxSplitRatio, (*SplitLayout).Grow, MaxSplitRatio},
+		{"shrink at the limit", MinSplitRatio, (*SplitLayout).Shrink, MinSplitRatio},
+	}
+	for _, tt := range tests {
+		l := SplitLayout{Ratio: tt.ratio}
+		tt.change(&l)
+		if l.Ratio != tt.want {
+			t.Errorf("%s: ratio = %d, want %d", tt.name, l.Ratio, tt.want)
+		}
+	}
+}
+
+func TestRectContains(t *testing.T) {
+	r := Rect{X: 2, Y: 1, Width: 3, Height: 2}
+	tests := []struct {
+		x, y int
+		want bool
+	}{
+		{2, 1, true},
+		{4, 2, true},
+		{5, 1, false},
+		{2, 3, false},
+		{1, 1, false},
+		{2, 0, false},
+	}
+	for _, tt := range tests {
+		if got := r.Contains(tt.x, tt.y); got != tt.want {
+			t.Errorf("Contains(%d, %d) = %v, want %v", tt.x, tt.y, got, tt.want)
+		}
+	}
+}
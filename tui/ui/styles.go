@@ -2,47 +2,49 @@ package ui
 
 import "github.com/charmbracelet/lipgloss"
 
+// The styles are rebuilt by SetTheme; these are the dark theme's
 var (
-	ColorCyan    = lipgloss.Color("6")
-	ColorYellow  = lipgloss.Color("3")
-	ColorGreen   = lipgloss.Color("2")
-	ColorMagenta = lipgloss.Color("5")
-	ColorDim     = lipgloss.Color("8")
-	ColorWhite   = lipgloss.Color("15")
-	ColorRed     = lipgloss.Color("1")
-	ColorBlue    = lipgloss.Color("4")
-
 	HeaderStyle = lipgloss.NewStyle().
-			Foreground(ColorCyan).
+			Foreground(DarkTheme.Header).
 			Bold(true)
 
 	PartNumberStyle = lipgloss.NewStyle().
-			Foreground(ColorYellow).
+			Foreground(DarkTheme.PartNumber).
 			Bold(true)
 
 	SelectedStyle = lipgloss.NewStyle().
-			Foreground(ColorGreen)
+			Foreground(DarkTheme.Selected)
 
 	SelectedLabelStyle = lipgloss.NewStyle().
-				Foreground(ColorYellow).
+				Foreground(DarkTheme.SelectedLabel).
 				Bold(true)
 
 	NormalLabelStyle = lipgloss.NewStyle().
-				Foreground(ColorWhite)
+				Foreground(DarkTheme.Text)
 
 	DimStyle = lipgloss.NewStyle().
-			Foreground(ColorDim)
+			Foreground(DarkTheme.Dim)
+
+	MatchStyle = lipgloss.NewStyle().
+			Foreground(DarkTheme.Match).
+			Bold(true)
 
 	CountStyle = lipgloss.NewStyle().
-			Foreground(ColorMagenta)
+			Foreground(DarkTheme.Count)
 
 	ErrorStyle = lipgloss.NewStyle().
-			Foreground(ColorRed)
+			Foreground(DarkTheme.Error)
 
 	LinkStyle = lipgloss.NewStyle().
-			Foreground(ColorBlue)
+			Foreground(DarkTheme.Link)
 
-	BoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			Padding(0, 1)
+	BoxStyle = boxStyle().
+			BorderForeground(DarkTheme.Border)
 )
+
+// boxStyle is the uncolored frame drawn around inputs and the palette
+func boxStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		Padding(0, 1)
+}
@@ -0,0 +1,165 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-isatty"
+	"github.com/muesli/termenv"
+)
+
+// Theme is a named set of colors for the styles. An empty color leaves
+// the terminal's own foreground.
+type Theme struct {
+	Name          string
+	Header        lipgloss.Color
+	PartNumber    lipgloss.Color
+	Selected      lipgloss.Color
+	SelectedLabel lipgloss.Color
+	Text          lipgloss.Color
+	Dim           lipgloss.Color
+	Count         lipgloss.Color
+	Error         lipgloss.Color
+	Link          lipgloss.Color
+	Match         lipgloss.Color
+	Border        lipgloss.Color // Input and palette boxes
+
+	// Mono themes ignore the colors and tell things apart with bold,
+	// underline, faint text and reverse video instead
+	Mono bool
+
+	// Glamour style for notes: "dark", "light" or "notty"
+	Markdown string
+}
+
+// The built-in themes
+var (
+	DarkTheme = Theme{
+		Name:          "dark",
+		Header:        "6",
+		PartNumber:    "3",
+		Selected:      "2",
+		SelectedLabel: "3",
+		Text:          "15",
+		Dim:           "8",
+		Count:         "5",
+		Error:         "1",
+		Link:          "4",
+		Match:         "2",
+		Border:        "8",
+		Markdown:      "dark",
+	}
+
+	// LightTheme avoids yellow and white, which wash out on a light
+	// background, and leaves text in the terminal's own color
+	LightTheme = Theme{
+		Name:          "light",
+		Header:        "4",
+		PartNumber:    "130",
+		Selected:      "28",
+		SelectedLabel: "130",
+		Dim:           "244",
+		Count:         "90",
+		Error:         "160",
+		Link:          "25",
+		Match:         "28",
+		Border:        "244",
+		Markdown:      "light",
+	}
+
+	// HighContrastTheme uses the bright colors, for a dark background
+	HighContrastTheme = Theme{
+		Name:          "high-contrast",
+		Header:        "14",
+		PartNumber:    "11",
+		Selected:      "10",
+		SelectedLabel: "11",
+		Text:          "15",
+		Dim:           "7",
+		Count:         "13",
+		Error:         "9",
+		Link:          "12",
+		Match:         "10",
+		Border:        "15",
+		Markdown:      "dark",
+	}
+
+	MonoTheme = Theme{
+		Name:     "mono",
+		Mono:     true,
+		Markdown: "notty",
+	}
+
+	Themes = []Theme{DarkTheme, LightTheme, HighContrastTheme, MonoTheme}
+)
+
+// ThemeNamed returns the built-in theme with a name
+func ThemeNamed(name string) (Theme, bool) {
+	for _, t := range Themes {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Theme{}, false
+}
+
+// DetectTheme picks the light or dark theme by asking the terminal for its
+// background color. It must run before the program takes over the terminal.
+func DetectTheme() Theme {
+	if lipgloss.HasDarkBackground() {
+		return DarkTheme
+	}
+	return LightTheme
+}
+
+// NoColor reports whether the NO_COLOR convention asks for no color
+func NoColor() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
+// current is the theme the styles were last built from
+var current = DarkTheme
+
+// CurrentTheme returns the theme in use
+func CurrentTheme() Theme {
+	return current
+}
+
+// SetTheme rebuilds the styles from a theme
+func SetTheme(t Theme) {
+	current = t
+
+	if t.Mono {
+		// NO_COLOR turns the color profile down to plain text, which would
+		// drop bold and reverse video as well. Those are still wanted on a
+		// terminal; only color is not.
+		if lipgloss.ColorProfile() == termenv.Ascii && isatty.IsTerminal(os.Stdout.Fd()) {
+			lipgloss.SetColorProfile(termenv.ANSI)
+		}
+
+		HeaderStyle = lipgloss.NewStyle().Bold(true).Underline(true)
+		PartNumberStyle = lipgloss.NewStyle().Bold(true)
+		SelectedStyle = lipgloss.NewStyle().Bold(true)
+		SelectedLabelStyle = lipgloss.NewStyle().Bold(true).Reverse(true)
+		NormalLabelStyle = lipgloss.NewStyle()
+		DimStyle = lipgloss.NewStyle().Faint(true)
+		MatchStyle = lipgloss.NewStyle().Bold(true).Underline(true)
+		CountStyle = lipgloss.NewStyle().Bold(true)
+		ErrorStyle = lipgloss.NewStyle().Bold(true)
+		LinkStyle = lipgloss.NewStyle().Underline(true)
+		BoxStyle = boxStyle()
+		return
+	}
+
+	HeaderStyle = lipgloss.NewStyle().Foreground(t.Header).Bold(true)
+	PartNumberStyle = lipgloss.NewStyle().Foreground(t.PartNumber).Bold(true)
+	SelectedStyle = lipgloss.NewStyle().Foreground(t.Selected)
+	SelectedLabelStyle = lipgloss.NewStyle().Foreground(t.SelectedLabel).Bold(true)
+	NormalLabelStyle = lipgloss.NewStyle().Foreground(t.Text)
+	DimStyle = lipgloss.NewStyle().Foreground(t.Dim)
+	MatchStyle = lipgloss.NewStyle().Foreground(t.Match).Bold(true)
+	CountStyle = lipgloss.NewStyle().Foreground(t.Count)
+	ErrorStyle = lipgloss.NewStyle().Foreground(t.Error)
+	LinkStyle = lipgloss.NewStyle().Foreground(t.Link)
+	BoxStyle = boxStyle().BorderForeground(t.Border)
+}